@@ -0,0 +1,70 @@
+package ki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// AbsoluteURL returns the parameterized URL resolved against the base URL, e.g. "https://example.com".
+// The path of the base URL prefixes the path of the location and the host of a route bound to a host, e.g.
+// "blog.example.com/posts", replaces the host of the base URL. The port of the base URL is kept unless the route is
+// bound to a port.
+// It returns an error if a path parameter is missing or unknown, or if the URL is invalid, see URLE.
+func (l Location) AbsoluteURL(base *url.URL) (*url.URL, error) {
+	if base == nil || base.Scheme == "" || base.Host == "" {
+		return nil, errors.New("base URL must be absolute")
+	}
+
+	host := base.Host
+
+	// The host of a route of a sub-router follows the prefix, e.g. "/api" and "blog.example.com/posts".
+	if i := strings.Index(l.pattern, "/"); i > 0 {
+		host = l.pattern[:i]
+		l.pattern = l.pattern[i:]
+
+		// The port of the pattern, if any, wins over the port of the base URL.
+		if _, _, err := net.SplitHostPort(host); err != nil && base.Port() != "" {
+			host = net.JoinHostPort(host, base.Port())
+		}
+	}
+
+	u, err := l.URLE()
+	if err != nil {
+		return nil, err
+	}
+
+	return &url.URL{
+		Scheme:   base.Scheme,
+		User:     base.User,
+		Host:     host,
+		Path:     strings.TrimSuffix(base.Path, "/") + u.Path,
+		RawPath:  strings.TrimSuffix(base.EscapedPath(), "/") + u.EscapedPath(),
+		RawQuery: u.RawQuery,
+	}, nil
+}
+
+// AbsoluteURL returns the absolute URL of the named route with the given path parameters from the registry and the
+// base URL in the context.
+// Use with Locator and BaseURL middlewares.
+func AbsoluteURL(ctx context.Context, name string, params ...string) (*url.URL, error) {
+	registry, ok := ctx.Value(registryContextKey).(*Registry)
+	if !ok {
+		return nil, errors.New("no registry in context")
+	}
+
+	base := GetBaseURL(ctx)
+	if base == nil {
+		return nil, errors.New("no base URL in context")
+	}
+
+	location, ok := registry.find(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown route %q", name)
+	}
+
+	return location.WithPathParams(params...).AbsoluteURL(base)
+}
@@ -0,0 +1,103 @@
+package ki
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestLocation_AbsoluteURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		location Location
+		expected string
+	}{
+		{
+			name:     "Host",
+			base:     "https://example.com",
+			location: NewLocation(http.MethodGet, "/posts/{id}").WithPathParams("a b").WithQueryParam("page", "2"),
+			expected: "https://example.com/posts/a%20b?page=2",
+		},
+		{
+			name:     "Base path",
+			base:     "https://example.com/app/",
+			location: NewLocation(http.MethodGet, "/posts").WithPrefix("/api"),
+			expected: "https://example.com/app/api/posts",
+		},
+		{
+			name:     "Host-specific route",
+			base:     "http://localhost:8080",
+			location: NewLocation(http.MethodGet, "blog.example.com/posts/{id}").WithPathParams("1"),
+			expected: "http://blog.example.com:8080/posts/1",
+		},
+		{
+			name:     "Host-specific route with port",
+			base:     "http://localhost:8080",
+			location: NewLocation(http.MethodGet, "blog.example.com:9090/posts/{id}").WithPathParams("1"),
+			expected: "http://blog.example.com:9090/posts/1",
+		},
+		{
+			name:     "Host-specific route of a sub-router",
+			base:     "https://example.com",
+			location: NewLocation(http.MethodGet, "blog.example.com/posts/{id}").WithPathParams("1").WithPrefix("/api"),
+			expected: "https://blog.example.com/api/posts/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, _ := url.Parse(tt.base)
+
+			u, err := tt.location.AbsoluteURL(base)
+			if err != nil {
+				t.Fatalf("Unexpected error: got=%v", err)
+			}
+
+			if u.String() != tt.expected {
+				t.Fatalf("Unexpected URL: got=%s, want=%s", u.String(), tt.expected)
+			}
+		})
+	}
+
+	if _, err := NewLocation(http.MethodGet, "/posts").AbsoluteURL(&url.URL{Path: "/app"}); err == nil {
+		t.Fatalf("Unexpected error: got=nil")
+	}
+
+	if _, err := NewLocation(http.MethodGet, "/posts/{id}").AbsoluteURL(&url.URL{Scheme: "https", Host: "example.com"}); err == nil {
+		t.Fatalf("Unexpected error: got=nil")
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	registry := NewRegistry()
+	registry.Child("/api").Add("get-post", http.MethodGet, "/posts/{id}")
+
+	base := &url.URL{Scheme: "https", Host: "example.com"}
+
+	ctx := SetBaseURL(SetRegistry(context.Background(), registry), base)
+
+	u, err := AbsoluteURL(ctx, "get-post", "1")
+	if err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	if u.String() != "https://example.com/api/posts/1" {
+		t.Fatalf("Unexpected URL: got=%s, want=%s", u.String(), "https://example.com/api/posts/1")
+	}
+
+	tests := map[string]context.Context{
+		"No registry":   SetBaseURL(context.Background(), base),
+		"No base URL":   SetRegistry(context.Background(), registry),
+		"Unknown route": SetBaseURL(SetRegistry(context.Background(), NewRegistry()), base),
+	}
+
+	for name, ctx := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := AbsoluteURL(ctx, "get-post", "1"); err == nil {
+				t.Fatalf("Unexpected error: got=nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package ki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// BatchRequest is a sub-request of a batch.
+type BatchRequest struct {
+	// ID identifies the sub-request for the dependencies.
+	ID string `json:"id,omitempty"`
+	// Method is the method of the sub-request, GET by default.
+	Method string `json:"method,omitempty"`
+	// Path is the path of the sub-request including the query.
+	Path string `json:"path"`
+	// Headers are added to the headers of the batch request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the body of the sub-request. A JSON string is sent unquoted.
+	Body json.RawMessage `json:"body,omitempty"`
+	// DependsOn lists the IDs of the sub-requests that must succeed before this one is dispatched.
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// BatchResponse is the response of a sub-request of a batch.
+type BatchResponse struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the body of the response, as is if it is valid JSON or as a JSON string otherwise.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// BatchOptions configures a batch handler.
+type BatchOptions struct {
+	// Concurrency is the maximum number of sub-requests dispatched in parallel, 1 by default.
+	Concurrency int
+	// MaxRequests is the maximum number of sub-requests in a batch, unlimited by default.
+	MaxRequests int
+}
+
+// batchContextKey marks the sub-requests of a batch.
+const batchContextKey contextKey = "batch"
+
+// Batch returns a handler that dispatches the sub-requests of a JSON batch through the given handler.
+// The handler is usually the router itself, so the sub-requests go through all its middlewares.
+// The failure of a sub-request never fails the batch; its dependents get a 424 Failed Dependency response.
+func Batch(handler http.Handler, options BatchOptions) http.Handler {
+	concurrency := max(options.Concurrency, 1)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Context().Value(batchContextKey) != nil {
+			http.Error(w, "nested batch", http.StatusBadRequest)
+			return
+		}
+
+		var requests []BatchRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			http.Error(w, "invalid batch", http.StatusBadRequest)
+			return
+		}
+
+		if options.MaxRequests > 0 && len(requests) > options.MaxRequests {
+			http.Error(w, "too many requests in batch", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		b := newBatch(handler, r, requests, concurrency)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(b.run())
+	})
+}
+
+// batch dispatches the sub-requests of a batch request.
+type batch struct {
+	handler   http.Handler
+	parent    *http.Request
+	requests  []BatchRequest
+	responses []BatchResponse
+	done      []chan struct{}
+	failed    []bool
+	semaphore chan struct{}
+}
+
+// newBatch returns a new batch.
+func newBatch(handler http.Handler, parent *http.Request, requests []BatchRequest, concurrency int) *batch {
+	b := &batch{
+		handler:   handler,
+		parent:    parent,
+		requests:  requests,
+		responses: make([]BatchResponse, len(requests)),
+		done:      make([]chan struct{}, len(requests)),
+		failed:    make([]bool, len(requests)),
+		semaphore: make(chan struct{}, concurrency),
+	}
+
+	for i := range requests {
+		b.done[i] = make(chan struct{})
+	}
+
+	return b
+}
+
+// run dispatches the sub-requests and returns their responses in order.
+func (b *batch) run() []BatchResponse {
+	dependencies, invalid := b.dependencies()
+
+	var wg sync.WaitGroup
+
+	for i := range b.requests {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(b.done[i])
+
+			if err := invalid[i]; err != nil {
+				b.fail(i, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			for _, dependency := range dependencies[i] {
+				<-b.done[dependency]
+
+				if b.failed[dependency] {
+					b.fail(i, http.StatusFailedDependency, fmt.Sprintf("dependency %s failed", b.requests[dependency].ID))
+					return
+				}
+			}
+
+			b.semaphore <- struct{}{}
+			defer func() { <-b.semaphore }()
+
+			b.dispatch(i)
+		}()
+	}
+
+	wg.Wait()
+
+	return b.responses
+}
+
+// dependencies returns the indexes of the dependencies of each sub-request and the sub-requests that cannot run.
+func (b *batch) dependencies() ([][]int, []error) {
+	indexes := map[string]int{}
+
+	for i, request := range b.requests {
+		if request.ID != "" {
+			indexes[request.ID] = i
+		}
+	}
+
+	dependencies := make([][]int, len(b.requests))
+	invalid := make([]error, len(b.requests))
+
+	for i, request := range b.requests {
+		for _, id := range request.DependsOn {
+			dependency, ok := indexes[id]
+			if !ok {
+				invalid[i] = fmt.Errorf("unknown dependency %s", id)
+				break
+			}
+
+			dependencies[i] = append(dependencies[i], dependency)
+		}
+	}
+
+	// The sub-requests in a dependency cycle would wait forever.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make([]int, len(b.requests))
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch states[i] {
+		case visiting:
+			return false
+		case visited:
+			return invalid[i] == nil
+		}
+
+		states[i] = visiting
+
+		for _, dependency := range dependencies[i] {
+			if !visit(dependency) && invalid[i] == nil {
+				invalid[i] = fmt.Errorf("dependency cycle")
+			}
+		}
+
+		states[i] = visited
+
+		return invalid[i] == nil
+	}
+
+	for i := range b.requests {
+		visit(i)
+	}
+
+	for i := range b.requests {
+		if invalid[i] != nil {
+			dependencies[i] = nil
+		}
+	}
+
+	return dependencies, invalid
+}
+
+// dispatch dispatches the sub-request through the handler.
+func (b *batch) dispatch(i int) {
+	request := b.requests[i]
+
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	if !strings.HasPrefix(request.Path, "/") {
+		b.fail(i, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	// A panicking sub-request only fails its own item, as net/http would recover it for a standalone request.
+	defer func() {
+		if err := recover(); err != nil {
+			GetLogger(b.parent.Context()).Error("batch request panic recovered",
+				slog.String("path", request.Path),
+				slog.Any("error", err),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			b.fail(i, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+	}()
+
+	ctx := context.WithValue(b.parent.Context(), batchContextKey, true)
+
+	// Each sub-request gets its own scope of request-scoped services, see ProvideScoped.
+	ctx = context.WithValue(ctx, containerContextKey, nil)
+
+	req, err := http.NewRequestWithContext(ctx, method, request.Path, strings.NewReader(batchBody(request.Body)))
+	if err != nil {
+		b.fail(i, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	req.Header = b.parent.Header.Clone()
+	req.Header.Del("Content-Length")
+	req.Header.Del("Content-Type")
+
+	for key, value := range request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	req.Host = b.parent.Host
+	req.RemoteAddr = b.parent.RemoteAddr
+	req.RequestURI = request.Path
+
+	capture := newResponseCapture()
+
+	b.handler.ServeHTTP(capture, req)
+
+	headers := map[string]string{}
+	for key, values := range capture.Header() {
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	b.responses[i] = BatchResponse{
+		ID:      request.ID,
+		Status:  capture.StatusCode(),
+		Headers: headers,
+		Body:    batchResponseBody(capture.body.Bytes()),
+	}
+
+	b.failed[i] = capture.StatusCode() >= http.StatusBadRequest
+}
+
+// fail sets a failed response for the sub-request.
+func (b *batch) fail(i int, status int, message string) {
+	b.responses[i] = BatchResponse{
+		ID:     b.requests[i].ID,
+		Status: status,
+		Body:   batchResponseBody([]byte(message)),
+	}
+
+	b.failed[i] = true
+}
+
+// batchBody returns the body of a sub-request.
+func batchBody(body json.RawMessage) string {
+	var s string
+
+	if err := json.Unmarshal(body, &s); err == nil {
+		return s
+	}
+
+	return string(body)
+}
+
+// batchResponseBody returns the body of a sub-response as JSON.
+func batchResponseBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if json.Valid(body) {
+		return body
+	}
+
+	s, _ := json.Marshal(string(body))
+
+	return s
+}
@@ -0,0 +1,212 @@
+package ki
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var middlewareCalls atomic.Int32
+
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			middlewareCalls.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	mux.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + r.PathValue("id") + `","user":"` + r.Header.Get("X-User") + `"}`))
+	})
+	mux.Post("/posts", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+	mux.Post("/batch", Batch(mux, BatchOptions{Concurrency: 2}).ServeHTTP)
+
+	body := `[
+		{"id": "get", "path": "/posts/1", "headers": {"X-User": "bob"}},
+		{"id": "create", "method": "POST", "path": "/posts", "body": "plain text"},
+		{"id": "after", "path": "/posts/2", "dependsOn": ["create"]},
+		{"id": "missing", "path": "/missing"},
+		{"id": "blocked", "path": "/posts/3", "dependsOn": ["missing"]},
+		{"id": "unknown", "path": "/posts/4", "dependsOn": ["nope"]},
+		{"id": "nested", "method": "POST", "path": "/batch", "body": []}
+	]`
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	req.Header.Set("X-User", "alice")
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var responses []BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		id     string
+		status int
+		body   string
+	}{
+		{id: "get", status: http.StatusOK, body: `{"id":"1","user":"bob"}`},
+		{id: "create", status: http.StatusCreated, body: `"plain text"`},
+		{id: "after", status: http.StatusOK, body: `{"id":"2","user":"alice"}`},
+		{id: "missing", status: http.StatusNotFound},
+		{id: "blocked", status: http.StatusFailedDependency},
+		{id: "unknown", status: http.StatusBadRequest},
+		{id: "nested", status: http.StatusBadRequest},
+	}
+
+	if len(responses) != len(expected) {
+		t.Fatalf("expected %d responses, got %d", len(expected), len(responses))
+	}
+
+	for i, e := range expected {
+		got := responses[i]
+
+		if got.ID != e.id {
+			t.Errorf("expected id %q at %d, got %q", e.id, i, got.ID)
+		}
+		if got.Status != e.status {
+			t.Errorf("expected status %d for %s, got %d", e.status, e.id, got.Status)
+		}
+		if e.body != "" && string(got.Body) != e.body {
+			t.Errorf("expected body %s for %s, got %s", e.body, e.id, got.Body)
+		}
+	}
+
+	// The batch request itself, then get, create, after and nested.
+	if got := middlewareCalls.Load(); got != 5 {
+		t.Errorf("expected 5 middleware calls, got %d", got)
+	}
+}
+
+func TestBatch_DependencyCycle(t *testing.T) {
+	mux := NewMux()
+	mux.Get("/ok", func(w http.ResponseWriter, r *http.Request) {})
+
+	handler := Batch(mux, BatchOptions{})
+
+	body := `[
+		{"id": "a", "path": "/ok", "dependsOn": ["b"]},
+		{"id": "b", "path": "/ok", "dependsOn": ["a"]},
+		{"id": "c", "path": "/ok"}
+	]`
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	var responses []BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	statuses := []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusOK}
+
+	for i, status := range statuses {
+		if responses[i].Status != status {
+			t.Errorf("expected status %d at %d, got %d", status, i, responses[i].Status)
+		}
+	}
+}
+
+func TestBatch_Invalid(t *testing.T) {
+	handler := Batch(NewMux(), BatchOptions{MaxRequests: 1})
+
+	tests := map[string]int{
+		`not json`:                       http.StatusBadRequest,
+		`[{"path": "/"}, {"path": "/"}]`: http.StatusRequestEntityTooLarge,
+	}
+
+	for body, status := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != status {
+			t.Errorf("expected status %d for %s, got %d", status, body, rec.Code)
+		}
+	}
+}
+
+func TestBatch_Panic(t *testing.T) {
+	mux := NewMux()
+	mux.Get("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.Get("/boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	mux.Post("/batch", Batch(mux, BatchOptions{Concurrency: 2}).ServeHTTP)
+
+	body := `[{"path": "/boom"}, {"path": "/ok"}]`
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	var responses []BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	statuses := []int{http.StatusInternalServerError, http.StatusOK}
+
+	for i, status := range statuses {
+		if responses[i].Status != status {
+			t.Errorf("expected status %d at %d, got %d", status, i, responses[i].Status)
+		}
+	}
+}
+
+func TestBatch_ScopedServices(t *testing.T) {
+	type session struct{}
+
+	var calls, cleanups atomic.Int32
+
+	mux := NewMux()
+	ProvideScoped(mux, func(r *http.Request) (*session, func()) {
+		calls.Add(1)
+		return &session{}, func() { cleanups.Add(1) }
+	})
+
+	mux.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		_ = MustResolve[*session](r.Context())
+	})
+	mux.Post("/batch", func(w http.ResponseWriter, r *http.Request) {
+		_ = MustResolve[*session](r.Context())
+
+		Batch(mux, BatchOptions{Concurrency: 3}).ServeHTTP(w, r)
+
+		if cleanups.Load() != 3 {
+			t.Errorf("expected the sub-request scopes to be closed, got %d cleanups", cleanups.Load())
+		}
+	})
+
+	body := `[{"path": "/ok"}, {"path": "/ok"}, {"path": "/ok"}]`
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+
+	if calls.Load() != 4 {
+		t.Errorf("expected one session per request, got %d", calls.Load())
+	}
+	if cleanups.Load() != 4 {
+		t.Errorf("expected one cleanup per request, got %d", cleanups.Load())
+	}
+}
@@ -0,0 +1,85 @@
+// Command routegen generates route helpers from the named routes of a ki registry exported as JSON, e.g.
+//
+//	json.NewEncoder(f).Encode(router.Registry().Routes())
+//
+// Usage:
+//
+//	routegen -in routes.json -out routes.ts
+//	routegen -in routes.json -out routes.go -lang go -package routes
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/throskam/ki"
+	"github.com/throskam/ki/routegen"
+)
+
+func main() {
+	in := flag.String("in", "", "the JSON file of the routes, defaults to the standard input")
+	out := flag.String("out", "", "the generated file, defaults to the standard output")
+	lang := flag.String("lang", "ts", "the language of the generated file, ts or go")
+	pkg := flag.String("package", "routes", "the package name of the generated Go file")
+
+	flag.Parse()
+
+	if err := run(*in, *out, *lang, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the route helpers of the routes read from in into out.
+func run(in, out, lang, pkg string) error {
+	var generate func(w io.Writer, routes []ki.RouteDescription) error
+
+	switch lang {
+	case "ts":
+		generate = routegen.TypeScript
+	case "go":
+		generate = func(w io.Writer, routes []ki.RouteDescription) error {
+			return routegen.Go(w, routes, routegen.GoOptions{Package: pkg})
+		}
+	default:
+		return fmt.Errorf("unknown language %q", lang)
+	}
+
+	var r io.Reader = os.Stdin
+
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		r = f
+	}
+
+	routes := []ki.RouteDescription{}
+
+	if err := json.NewDecoder(r).Decode(&routes); err != nil {
+		return fmt.Errorf("decode routes: %w", err)
+	}
+
+	if out == "" {
+		return generate(os.Stdout, routes)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	if err := generate(f, routes); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
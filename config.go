@@ -0,0 +1,190 @@
+package ki
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RouterConfig describes the routes, groups, sub-routers, mounts and middlewares of a router.
+type RouterConfig struct {
+	// Prefix is the prefix of a sub-router. It is ignored for the top-level router and the groups.
+	Prefix  string             `json:"prefix,omitempty"`
+	Use     []MiddlewareConfig `json:"use,omitempty"`
+	Routes  []RouteConfig      `json:"routes,omitempty"`
+	Groups  []RouterConfig     `json:"groups,omitempty"`
+	Routers []RouterConfig     `json:"routers,omitempty"`
+	Mounts  []MountConfig      `json:"mounts,omitempty"`
+}
+
+// RouteConfig describes a route.
+type RouteConfig struct {
+	Method  string             `json:"method"`
+	Pattern string             `json:"pattern"`
+	Handler string             `json:"handler"`
+	Name    string             `json:"name,omitempty"`
+	Use     []MiddlewareConfig `json:"use,omitempty"`
+}
+
+// MountConfig describes a mounted handler.
+type MountConfig struct {
+	Prefix  string `json:"prefix"`
+	Handler string `json:"handler"`
+}
+
+// MiddlewareConfig describes a middleware of the catalog and its arguments.
+type MiddlewareConfig struct {
+	Use  string          `json:"use"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// MiddlewareCatalog builds the middlewares by name.
+type MiddlewareCatalog interface {
+	// Middleware returns the middleware registered with the given name configured with the given arguments.
+	Middleware(name string, args json.RawMessage, router Router) (func(http.Handler) http.Handler, error)
+}
+
+// LoadConfig decodes the JSON router configuration.
+func LoadConfig(r io.Reader) (RouterConfig, error) {
+	var config RouterConfig
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&config); err != nil {
+		return RouterConfig{}, fmt.Errorf("cannot decode router config: %w", err)
+	}
+
+	return config, nil
+}
+
+// Assemble adds the routes described by the configuration to the router.
+// The handlers are referenced by name in the given map and the middlewares are built by the catalog.
+// It returns an error, instead of panicking, if a route or a mount cannot be registered, e.g. on an invalid pattern or a
+// duplicate route name.
+func Assemble(router Router, config RouterConfig, handlers map[string]http.Handler, catalog MiddlewareCatalog) error {
+	a := assembler{
+		root:     router,
+		handlers: handlers,
+		catalog:  catalog,
+	}
+
+	return a.assemble(router, config)
+}
+
+// assembler adds the configured routes to a router.
+type assembler struct {
+	root     Router
+	handlers map[string]http.Handler
+	catalog  MiddlewareCatalog
+}
+
+// assemble adds the configured routes to the given router.
+func (a assembler) assemble(router Router, config RouterConfig) error {
+	middlewares, err := a.middlewares(config.Use)
+	if err != nil {
+		return err
+	}
+
+	if len(middlewares) > 0 {
+		router.Use(middlewares...)
+	}
+
+	for _, route := range config.Routes {
+		handler, err := a.handler(route.Handler)
+		if err != nil {
+			return fmt.Errorf("route %s %s: %w", route.Method, route.Pattern, err)
+		}
+
+		middlewares, err := a.middlewares(route.Use)
+		if err != nil {
+			return fmt.Errorf("route %s %s: %w", route.Method, route.Pattern, err)
+		}
+
+		options := []RouteOption{WithMiddleware(middlewares...)}
+
+		if route.Name != "" {
+			options = append(options, WithName(route.Name))
+		}
+
+		err = register(func() { router.Method(route.Method, route.Pattern, handler.ServeHTTP, options...) })
+		if err != nil {
+			return fmt.Errorf("route %s %s: %w", route.Method, route.Pattern, err)
+		}
+	}
+
+	for _, group := range config.Groups {
+		err = a.assemble(router.Group(nil), group)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range config.Routers {
+		var child Router
+
+		err = register(func() { child = router.Route(sub.Prefix, nil) })
+		if err == nil {
+			err = a.assemble(child, sub)
+		}
+
+		if err != nil {
+			return fmt.Errorf("router %s: %w", sub.Prefix, err)
+		}
+	}
+
+	for _, mount := range config.Mounts {
+		handler, err := a.handler(mount.Handler)
+		if err != nil {
+			return fmt.Errorf("mount %s: %w", mount.Prefix, err)
+		}
+
+		err = register(func() { router.Mount(mount.Prefix, handler) })
+		if err != nil {
+			return fmt.Errorf("mount %s: %w", mount.Prefix, err)
+		}
+	}
+
+	return nil
+}
+
+// register calls fn, which registers a route, turning a panic into an error, e.g. on an invalid pattern, a duplicate
+// pattern or a duplicate route name.
+func register(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	fn()
+
+	return nil
+}
+
+// handler returns the handler registered with the given name.
+func (a assembler) handler(name string) (http.Handler, error) {
+	handler, ok := a.handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown handler %q", name)
+	}
+
+	return handler, nil
+}
+
+// middlewares builds the configured middlewares.
+func (a assembler) middlewares(configs []MiddlewareConfig) ([]func(http.Handler) http.Handler, error) {
+	middlewares := []func(http.Handler) http.Handler{}
+
+	for _, config := range configs {
+		middleware, err := a.catalog.Middleware(config.Use, config.Args, a.root)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %q: %w", config.Use, err)
+		}
+
+		middlewares = append(middlewares, middleware)
+	}
+
+	return middlewares, nil
+}
@@ -0,0 +1,179 @@
+package ki
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type headerCatalog struct{}
+
+func (headerCatalog) Middleware(name string, args json.RawMessage, router Router) (func(http.Handler) http.Handler, error) {
+	if name != "header" {
+		return nil, fmt.Errorf("unknown middleware %q", name)
+	}
+
+	var value string
+	if err := json.Unmarshal(args, &value); err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", value)
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+func TestAssemble(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(`{
+		"use": [{"use": "header", "args": "root"}],
+		"routes": [{"method": "GET", "pattern": "/{$}", "handler": "home", "name": "home"}],
+		"groups": [{
+			"use": [{"use": "header", "args": "group"}],
+			"routes": [{"method": "GET", "pattern": "/about", "handler": "home"}]
+		}],
+		"routers": [{
+			"prefix": "/posts",
+			"routes": [{
+				"method": "GET",
+				"pattern": "/{id}",
+				"handler": "post",
+				"name": "get-post",
+				"use": [{"use": "header", "args": "route"}]
+			}]
+		}],
+		"mounts": [{"prefix": "/admin", "handler": "admin"}]
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	handlers := map[string]http.Handler{
+		"home": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("home"))
+		}),
+		"post": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("post " + r.PathValue("id")))
+		}),
+		"admin": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("admin " + r.URL.Path))
+		}),
+	}
+
+	mux := NewMux()
+
+	err = Assemble(mux, config, handlers, headerCatalog{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		path        string
+		body        string
+		middlewares string
+	}{
+		{path: "/", body: "home", middlewares: "root"},
+		{path: "/about", body: "home", middlewares: "root,group"},
+		{path: "/posts/1", body: "post 1", middlewares: "root,route"},
+		{path: "/admin/users", body: "admin /users", middlewares: "root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.body {
+				t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.body)
+			}
+			if got := strings.Join(rec.Header().Values("X-Middleware"), ","); got != tt.middlewares {
+				t.Fatalf("Unexpected middlewares for %s: got=%q, want=%q", tt.path, got, tt.middlewares)
+			}
+		})
+	}
+
+	if got := mux.Registry().Get("get-post").WithPathParams("1").URL().String(); got != "/posts/1" {
+		t.Fatalf("Incorrect path: got=%s, want=%s", got, "/posts/1")
+	}
+}
+
+func TestAssemble_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   RouterConfig
+		expected string
+	}{
+		{
+			name:   "Unknown handler",
+			config: RouterConfig{Routes: []RouteConfig{{Method: http.MethodGet, Pattern: "/", Handler: "missing"}}},
+		},
+		{
+			name:   "Unknown middleware",
+			config: RouterConfig{Use: []MiddlewareConfig{{Use: "missing"}}},
+		},
+		{
+			name:   "Unknown mount handler",
+			config: RouterConfig{Mounts: []MountConfig{{Prefix: "/admin", Handler: "missing"}}},
+		},
+		{
+			name: "Duplicate name",
+			config: RouterConfig{Routes: []RouteConfig{
+				{Method: http.MethodGet, Pattern: "/a", Handler: "ok", Name: "page"},
+				{Method: http.MethodGet, Pattern: "/b", Handler: "ok", Name: "page"},
+			}},
+			expected: "route GET /b: Location page already exists",
+		},
+		{
+			name:     "Invalid pattern",
+			config:   RouterConfig{Routes: []RouteConfig{{Method: http.MethodGet, Pattern: "/{bad", Handler: "ok"}}},
+			expected: "route GET /{bad: ",
+		},
+		{
+			name: "Duplicate pattern",
+			config: RouterConfig{Routes: []RouteConfig{
+				{Method: http.MethodGet, Pattern: "/a", Handler: "ok"},
+				{Method: http.MethodGet, Pattern: "/a", Handler: "ok"},
+			}},
+			expected: "route GET /a: ",
+		},
+		{
+			name: "Duplicate mount",
+			config: RouterConfig{Mounts: []MountConfig{
+				{Prefix: "/admin", Handler: "ok"},
+				{Prefix: "/admin", Handler: "ok"},
+			}},
+			expected: "mount /admin: ",
+		},
+	}
+
+	handlers := map[string]http.Handler{
+		"ok": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Assemble(NewMux(), tt.config, handlers, headerCatalog{})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Fatalf("Unexpected error: got=%v, want=%s...", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	_, err := LoadConfig(strings.NewReader(`{"routez": []}`))
+	if err == nil {
+		t.Fatal("expected error on unknown field, got nil")
+	}
+}
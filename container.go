@@ -0,0 +1,221 @@
+package ki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Container is a typed service container attached to a router.
+// The container of a sub-router or a group inherits the services of its parent and can override them.
+type Container struct {
+	parent *Container
+
+	mu        sync.RWMutex
+	providers map[reflect.Type]provider
+}
+
+// provider provides a service, either as a value or as a request-scoped factory.
+type provider struct {
+	value   any
+	factory func(*http.Request) (any, func())
+}
+
+// scope holds the request-scoped services of a request.
+type scope struct {
+	mu        sync.Mutex
+	container *Container
+	request   *http.Request
+	instances map[instanceKey]*instance
+	cleanups  []func()
+}
+
+// instanceKey identifies a request-scoped service by the container providing it, so that a service overridden by a
+// sub-router is not shared with its parent.
+type instanceKey struct {
+	container *Container
+	t         reflect.Type
+}
+
+// instance is a request-scoped service, created once by its factory.
+type instance struct {
+	once  sync.Once
+	value any
+}
+
+// NewContainer returns a new Container.
+func NewContainer() *Container {
+	return &Container{
+		providers: map[reflect.Type]provider{},
+	}
+}
+
+// Child returns a new child container inheriting the services of the container.
+func (c *Container) Child() *Container {
+	container := NewContainer()
+	container.parent = c
+
+	return container
+}
+
+// Provide registers the value as the service of type T in the container of the router.
+func Provide[T any](router Router, value T) {
+	router.Container().set(reflect.TypeFor[T](), provider{value: value})
+}
+
+// ProvideScoped registers the factory of the service of type T in the container of the router.
+// The factory is called at most once per request and the returned cleanup function, if any, is called at the end of
+// the request.
+func ProvideScoped[T any](router Router, factory func(r *http.Request) (T, func())) {
+	router.Container().set(reflect.TypeFor[T](), provider{factory: func(r *http.Request) (any, func()) {
+		return factory(r)
+	}})
+}
+
+// Resolve returns the service of type T for the request in the context.
+func Resolve[T any](ctx context.Context) (T, bool) {
+	var zero T
+
+	s, ok := ctx.Value(containerContextKey).(*scope)
+	if !ok {
+		return zero, false
+	}
+
+	value, ok := s.resolve(reflect.TypeFor[T]())
+	if !ok {
+		return zero, false
+	}
+
+	// A service provided as a nil interface resolves to the zero value.
+	typed, _ := value.(T)
+
+	return typed, true
+}
+
+// MustResolve returns the service of type T for the request in the context.
+// It panics if the service is not provided.
+func MustResolve[T any](ctx context.Context) T {
+	value, ok := Resolve[T](ctx)
+	if !ok {
+		panic(fmt.Sprintf("no service %s", reflect.TypeFor[T]()))
+	}
+
+	return value
+}
+
+// set sets the provider of the given type.
+func (c *Container) set(t reflect.Type, p provider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.providers[t] = p
+}
+
+// get returns the provider of the given type and the container providing it, either the container or a parent.
+func (c *Container) get(t reflect.Type) (provider, *Container, bool) {
+	for container := c; container != nil; container = container.parent {
+		container.mu.RLock()
+		p, ok := container.providers[t]
+		container.mu.RUnlock()
+
+		if ok {
+			return p, container, true
+		}
+	}
+
+	return provider{}, nil, false
+}
+
+// resolve returns the service of the given type, calling its factory if necessary.
+// The factory is called without holding the lock of the scope, so that it can resolve other services.
+func (s *scope) resolve(t reflect.Type) (any, bool) {
+	s.mu.Lock()
+	container, request := s.container, s.request
+	s.mu.Unlock()
+
+	p, owner, ok := container.get(t)
+	if !ok {
+		return nil, false
+	}
+
+	if p.factory == nil {
+		return p.value, true
+	}
+
+	key := instanceKey{container: owner, t: t}
+
+	s.mu.Lock()
+	i, ok := s.instances[key]
+	if !ok {
+		i = &instance{}
+		s.instances[key] = i
+	}
+	s.mu.Unlock()
+
+	i.once.Do(func() {
+		value, cleanup := p.factory(request)
+
+		i.value = value
+
+		if cleanup != nil {
+			s.mu.Lock()
+			s.cleanups = append(s.cleanups, cleanup)
+			s.mu.Unlock()
+		}
+	})
+
+	return i.value, true
+}
+
+// close calls the cleanup functions in the reverse order.
+func (s *scope) close() {
+	s.mu.Lock()
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+
+	for _, cleanup := range slices.Backward(cleanups) {
+		cleanup()
+	}
+}
+
+// withContainer returns a handler that resolves the services from the given container.
+// The request scope is created by the outermost router and closed at the end of the request.
+func withContainer(container *Container, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := r.Context().Value(containerContextKey).(*scope)
+		if ok {
+			s.mu.Lock()
+			previous, request := s.container, s.request
+			s.container = container
+			s.request = r
+			s.mu.Unlock()
+
+			// The caller resolves from its own container again once the sub-router is done.
+			defer func() {
+				s.mu.Lock()
+				s.container = previous
+				s.request = request
+				s.mu.Unlock()
+			}()
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s = &scope{
+			container: container,
+			instances: map[instanceKey]*instance{},
+		}
+
+		defer s.close()
+
+		r = r.WithContext(context.WithValue(r.Context(), containerContextKey, s))
+		s.request = r
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,257 @@
+package ki
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type staticGreeter string
+
+func (g staticGreeter) Greet() string {
+	return string(g)
+}
+
+func TestContainer_ProvideAndResolve(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(MustResolve[greeter](r.Context()).Greet()))
+	}
+
+	mux := NewMux()
+	Provide[greeter](mux, staticGreeter("hello"))
+
+	mux.Get("/{$}", handler)
+	mux.Route("/fr", func(r Router) {
+		Provide[greeter](r, staticGreeter("bonjour"))
+		r.Get("/{$}", handler)
+	})
+	mux.Route("/inherit", func(r Router) {
+		r.Get("/{$}", handler)
+		r.Group(func(r Router) {
+			Provide[greeter](r, staticGreeter("hallo"))
+			r.Get("/de", handler)
+		})
+	})
+
+	tests := map[string]string{
+		"/":           "hello",
+		"/fr/":        "bonjour",
+		"/inherit/":   "hello",
+		"/inherit/de": "hallo",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Body.String() != expected {
+				t.Fatalf("Unexpected body for %s: got=%q, want=%q", path, rec.Body.String(), expected)
+			}
+		})
+	}
+}
+
+func TestContainer_ProvideScoped(t *testing.T) {
+	type session struct {
+		user string
+	}
+
+	var calls, cleanups int
+
+	mux := NewMux()
+	ProvideScoped(mux, func(r *http.Request) (*session, func()) {
+		calls++
+		return &session{user: r.Header.Get("X-User")}, func() { cleanups++ }
+	})
+
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = MustResolve[*session](r.Context())
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	mux.Route("/me", func(r Router) {
+		r.Get("/{$}", func(w http.ResponseWriter, r *http.Request) {
+			if cleanups != 0 {
+				t.Errorf("expected cleanup after the request, got %d", cleanups)
+			}
+
+			_, _ = w.Write([]byte(MustResolve[*session](r.Context()).user))
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me/", nil)
+	req.Header.Set("X-User", "alice")
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "alice" {
+		t.Fatalf("expected body %q, got %q", "alice", rec.Body.String())
+	}
+	if calls != 1 {
+		t.Fatalf("expected factory to be called once, got %d", calls)
+	}
+	if cleanups != 1 {
+		t.Fatalf("expected cleanup to be called once, got %d", cleanups)
+	}
+}
+
+func TestContainer_ResolveMissing(t *testing.T) {
+	if _, ok := Resolve[greeter](context.Background()); ok {
+		t.Fatal("expected no service without a request scope")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on missing service")
+		}
+	}()
+
+	mux := NewMux()
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		MustResolve[greeter](r.Context())
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestContainer_ScopedDependencies(t *testing.T) {
+	type db struct {
+		name string
+	}
+
+	type tx struct {
+		db *db
+	}
+
+	var cleanups []string
+
+	mux := NewMux()
+	ProvideScoped(mux, func(r *http.Request) (*db, func()) {
+		return &db{name: "main"}, func() { cleanups = append(cleanups, "db") }
+	})
+	ProvideScoped(mux, func(r *http.Request) (*tx, func()) {
+		return &tx{db: MustResolve[*db](r.Context())}, func() { cleanups = append(cleanups, "tx") }
+	})
+
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(MustResolve[*tx](r.Context()).db.name))
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- rec
+	}()
+
+	select {
+	case rec := <-done:
+		if rec.Body.String() != "main" {
+			t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), "main")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Unexpected deadlock resolving a scoped dependency")
+	}
+
+	if len(cleanups) != 2 || cleanups[0] != "tx" || cleanups[1] != "db" {
+		t.Fatalf("Unexpected cleanups: got=%v, want=%v", cleanups, []string{"tx", "db"})
+	}
+}
+
+func TestContainer_ScopedOverride(t *testing.T) {
+	mux := NewMux()
+	ProvideScoped(mux, func(r *http.Request) (greeter, func()) {
+		return staticGreeter("hello"), nil
+	})
+
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = MustResolve[greeter](r.Context())
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	mux.Route("/fr", func(r Router) {
+		ProvideScoped(r, func(r *http.Request) (greeter, func()) {
+			return staticGreeter("bonjour"), nil
+		})
+		r.Get("/{$}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(MustResolve[greeter](r.Context()).Greet()))
+		})
+	})
+
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fr/", nil))
+
+	if rec.Body.String() != "bonjour" {
+		t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), "bonjour")
+	}
+}
+
+func TestContainer_ResolveAfterForward(t *testing.T) {
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := SetRouter(SetRegistry(r.Context(), mux.Registry()), mux)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	Provide[greeter](mux, staticGreeter("root"))
+
+	mux.Route("/other", func(r Router) {
+		Provide[greeter](r, staticGreeter("other"))
+		r.Get("/{$}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(MustResolve[greeter](r.Context()).Greet()))
+		}, WithName("other"))
+	})
+
+	mux.Get("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		res, err := ForwardCapture(r, "other")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(res.Body)
+
+		_, _ = w.Write([]byte(string(body) + " " + MustResolve[greeter](r.Context()).Greet()))
+	})
+
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Body.String() != "other root" {
+		t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), "other root")
+	}
+}
+
+func TestContainer_ResolveNil(t *testing.T) {
+	mux := NewMux()
+	Provide[error](mux, nil)
+
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		err, ok := Resolve[error](r.Context())
+		if !ok || err != nil {
+			t.Errorf("Unexpected service: got=%v, %v, want=<nil>, true", err, ok)
+		}
+	})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
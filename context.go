@@ -3,6 +3,7 @@ package ki
 import (
 	"context"
 	"log/slog"
+	"net/url"
 
 	"golang.org/x/text/language"
 )
@@ -10,10 +11,15 @@ import (
 type contextKey string
 
 const (
-	languageContextKey  contextKey = "language"
-	loggerContextKey    contextKey = "logger"
-	registryContextKey  contextKey = "registry"
-	requestIDContextKey contextKey = "request-id"
+	baseURLContextKey    contextKey = "base-url"
+	containerContextKey  contextKey = "container"
+	forwardContextKey    contextKey = "forward"
+	languageContextKey   contextKey = "language"
+	loggerContextKey     contextKey = "logger"
+	pathValuesContextKey contextKey = "path-values"
+	registryContextKey   contextKey = "registry"
+	routerContextKey     contextKey = "router"
+	requestIDContextKey  contextKey = "request-id"
 )
 
 // GetLocation returns the location for the given key from the registry in the context.
@@ -29,6 +35,32 @@ func SetRegistry(ctx context.Context, registry *Registry) context.Context {
 	return context.WithValue(ctx, registryContextKey, registry)
 }
 
+// GetBaseURL returns the base URL from the context or nil if not set.
+// Use with BaseURL middleware.
+func GetBaseURL(ctx context.Context) *url.URL {
+	base, ok := ctx.Value(baseURLContextKey).(*url.URL)
+	if !ok {
+		return nil
+	}
+
+	return base
+}
+
+// SetBaseURL sets the base URL in the context.
+func SetBaseURL(ctx context.Context, base *url.URL) context.Context {
+	return context.WithValue(ctx, baseURLContextKey, base)
+}
+
+// SetRouter sets the router in the context.
+func SetRouter(ctx context.Context, router Router) context.Context {
+	return context.WithValue(ctx, routerContextKey, router)
+}
+
+// setPathValues sets the path values inherited from the parent routers in the context.
+func setPathValues(ctx context.Context, values map[string]string) context.Context {
+	return context.WithValue(ctx, pathValuesContextKey, values)
+}
+
 // GetRequestID returns the request ID from the context.
 // Use with RequestID middleware.
 func GetRequestID(ctx context.Context) string {
@@ -74,8 +106,19 @@ func MustGetLogger(ctx context.Context) *slog.Logger {
 	return logger
 }
 
+// GetLogger returns the logger from the context.
+// If the logger is not set, it returns the global logger.
+// Use with Logger middleware.
+func GetLogger(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerContextKey).(*slog.Logger)
+	if !ok {
+		return Logger
+	}
+
+	return logger
+}
+
 // SetLogger sets the logger in the context.
 func SetLogger(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerContextKey, logger)
 }
-
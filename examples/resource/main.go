@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/throskam/ki"
+	"github.com/throskam/ki/middlewares"
+)
+
+type posts struct{}
+
+func (posts) Index(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("list posts\n"))
+}
+
+func (posts) Create(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("create a new post\n"))
+}
+
+func (posts) Show(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "get post %s\n", r.PathValue("id"))
+}
+
+func (posts) Destroy(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "delete post %s\n", r.PathValue("id"))
+}
+
+type comments struct{}
+
+func (comments) Index(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "list comments of post %s\n", r.PathValue("postID"))
+}
+
+func (comments) Show(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "get comment %s of post %s\n", r.PathValue("id"), r.PathValue("postID"))
+}
+
+func main() {
+	router := ki.NewRouter()
+
+	router.Use(middlewares.Locator(router))
+
+	// GET /posts/, POST /posts/, GET /posts/{id}, DELETE /posts/{id}
+	router.Resource("/posts", posts{}, func(r ki.Router) {
+		// GET /posts/{postID}/comments/, GET /posts/{postID}/comments/{id}
+		r.Resource("/{postID}/comments", comments{}, nil)
+	})
+
+	router.Get("/named-routes", func(w http.ResponseWriter, r *http.Request) {
+		getPostLocation := ki.GetLocation(r.Context(), "posts.show").WithPathParams("1234")
+		getPostCommentLocation := ki.GetLocation(r.Context(), "posts.comments.show").WithPathParams("1234", "5678")
+
+		_, _ = fmt.Fprintf(w, "get post: %s %s\n", getPostLocation.Method(), getPostLocation.URL())
+		_, _ = fmt.Fprintf(w, "get post comment: %s %s\n", getPostCommentLocation.Method(), getPostCommentLocation.URL())
+	})
+
+	_ = http.ListenAndServe(":8080", router)
+}
@@ -69,9 +69,10 @@ func main() {
 	// GET /admin/dashboard
 	admin.Get("/dashboard", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("dashboard\n"))
-	})
+	}, ki.WithName("admin-dashboard"))
 
 	// Mount the admin router at the given prefix.
+	// Its named routes are resolved with the prefix, e.g. "admin-dashboard" is /admin/dashboard.
 	router.Mount("/admin", admin)
 
 	_ = http.ListenAndServe(":8080", router)
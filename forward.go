@@ -0,0 +1,111 @@
+package ki
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// maxForwardDepth is the maximum number of nested forwards.
+const maxForwardDepth = 10
+
+// ErrForwardLoop is returned when a forward would dispatch to a route already in the forward chain.
+var ErrForwardLoop = errors.New("forward loop")
+
+// Forward dispatches the request internally to the named route and writes its response to w.
+// The router and the registry are taken from the context.
+// Use with Locator middleware.
+func Forward(w http.ResponseWriter, r *http.Request, name string, params ...string) error {
+	req, router, err := forwardRequest(r, name, params...)
+	if err != nil {
+		return err
+	}
+
+	router.ServeHTTP(w, req)
+
+	return nil
+}
+
+// ForwardCapture dispatches the request internally to the named route and returns its response instead of writing it.
+// Use with Locator middleware.
+func ForwardCapture(r *http.Request, name string, params ...string) (*http.Response, error) {
+	req, router, err := forwardRequest(r, name, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	capture := newResponseCapture()
+
+	router.ServeHTTP(capture, req)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", capture.StatusCode(), http.StatusText(capture.StatusCode())),
+		StatusCode:    capture.StatusCode(),
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        capture.Header(),
+		Body:          io.NopCloser(bytes.NewReader(capture.body.Bytes())),
+		ContentLength: int64(capture.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// forwardRequest returns the sub-request for the named route and the router to dispatch it.
+func forwardRequest(r *http.Request, name string, params ...string) (*http.Request, Router, error) {
+	ctx := r.Context()
+
+	router, ok := ctx.Value(routerContextKey).(Router)
+	if !ok {
+		return nil, nil, errors.New("no router in context")
+	}
+
+	chain, _ := ctx.Value(forwardContextKey).([]string)
+
+	if slices.Contains(chain, name) || len(chain) >= maxForwardDepth {
+		return nil, nil, fmt.Errorf("%w: %v -> %s", ErrForwardLoop, chain, name)
+	}
+
+	if !router.Registry().Has(name) {
+		return nil, nil, fmt.Errorf("unknown route %q", name)
+	}
+
+	location := router.Registry().Get(name).WithPathParams(params...)
+
+	// The locations include the prefixes of the sub-routers, which only match the path below their prefix.
+	if mux, ok := router.(*Mux); ok {
+		router = mux.root()
+		location = mux.root().registry.relative(location)
+	}
+
+	u, err := location.URLE()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot resolve route %q: %w", name, err)
+	}
+
+	method := location.Method()
+	if method == "" {
+		method = r.Method
+	}
+
+	ctx = context.WithValue(ctx, forwardContextKey, append(slices.Clone(chain), name))
+
+	// The sub-request gets its own scope of request-scoped services, see ProvideScoped.
+	ctx = context.WithValue(ctx, containerContextKey, nil)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create forward request: %w", err)
+	}
+
+	req.Header = r.Header.Clone()
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	req.RequestURI = req.URL.RequestURI()
+
+	return req, router, nil
+}
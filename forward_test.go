@@ -0,0 +1,208 @@
+package ki
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForward(t *testing.T) {
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := SetRouter(SetRegistry(r.Context(), mux.Registry()), mux)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	mux.Route("/v2", func(r Router) {
+		r.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Served-By", "v2")
+			_, _ = w.Write([]byte("post " + r.PathValue("id") + " for " + r.Header.Get("X-User")))
+		}, WithName("get-post"))
+	})
+
+	mux.Get("/legacy/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := Forward(w, r, "get-post", r.PathValue("id")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	mux.Get("/include", func(w http.ResponseWriter, r *http.Request) {
+		res, err := ForwardCapture(r, "get-post", "2")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(res.Body)
+
+		_, _ = w.Write([]byte("<div>" + string(body) + "</div>"))
+	})
+
+	mux.Get("/loop", func(w http.ResponseWriter, r *http.Request) {
+		err := Forward(w, r, "loop")
+		if errors.Is(err, ErrForwardLoop) {
+			w.WriteHeader(http.StatusLoopDetected)
+		}
+	}, WithName("loop"))
+
+	mux.Get("/unknown", func(w http.ResponseWriter, r *http.Request) {
+		if err := Forward(w, r, "unknown"); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{path: "/legacy/posts/1", code: http.StatusOK, body: "post 1 for alice"},
+		{path: "/include", code: http.StatusOK, body: "<div>post 2 for alice</div>"},
+		{path: "/loop", code: http.StatusLoopDetected},
+		{path: "/unknown", code: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("X-User", "alice")
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("Unexpected status for %s: got=%d, want=%d", tt.path, rec.Code, tt.code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestForward_NoRouter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if err := Forward(httptest.NewRecorder(), req, "home"); err == nil {
+		t.Fatal("expected error without router in context, got nil")
+	}
+}
+
+func TestForward_ScopedServices(t *testing.T) {
+	type session struct {
+		id int
+	}
+
+	var calls, cleanups int
+
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := SetRouter(SetRegistry(r.Context(), mux.Registry()), mux)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	ProvideScoped(mux, func(r *http.Request) (*session, func()) {
+		calls++
+		return &session{id: calls}, func() { cleanups++ }
+	})
+
+	mux.Get("/target", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%d", MustResolve[*session](r.Context()).id)
+	}, WithName("target"))
+
+	mux.Get("/source", func(w http.ResponseWriter, r *http.Request) {
+		caller := MustResolve[*session](r.Context())
+
+		res, err := ForwardCapture(r, "target")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(res.Body)
+
+		_, _ = fmt.Fprintf(w, "%d %s %d", caller.id, body, cleanups)
+	})
+
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/source", nil))
+
+	if rec.Body.String() != "1 2 1" {
+		t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), "1 2 1")
+	}
+}
+
+func TestForward_SubRouter(t *testing.T) {
+	locator := func(router Router) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := SetRouter(SetRegistry(r.Context(), router.Registry()), router)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		}
+	}
+
+	forward := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if err := Forward(w, r, name, "1"); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux(option)
+
+			mux.Route("/v2", func(r Router) {
+				r.Use(locator(r))
+
+				r.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("post " + r.PathValue("id")))
+				}, WithName("get-post"))
+
+				r.Get("/legacy/posts/{id}", forward("get-post"))
+			})
+
+			api := NewMux(option)
+			api.Use(locator(api))
+
+			api.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("user " + r.PathValue("id")))
+			}, WithName("get-user"))
+
+			api.Get("/legacy/users/{id}", forward("get-user"))
+
+			mux.Mount("/api", api)
+
+			tests := []struct {
+				path string
+				body string
+			}{
+				{path: "/v2/legacy/posts/1", body: "post 1"},
+				{path: "/api/legacy/users/1", body: "user 1"},
+			}
+
+			for _, tt := range tests {
+				rec := httptest.NewRecorder()
+
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Unexpected status for %s: got=%d, want=%d", tt.path, rec.Code, http.StatusOK)
+				}
+				if rec.Body.String() != tt.body {
+					t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.body)
+				}
+			}
+		})
+	}
+}
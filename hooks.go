@@ -0,0 +1,125 @@
+package ki
+
+import (
+	"net/http"
+	"time"
+)
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	// Name is the name of the route, empty if the route is not named.
+	Name string
+	// Method is the method of the route, empty if the route matches any method.
+	Method string
+	// Path is the full path of the route including the prefix of the router.
+	Path string
+	// Mount is true if the route mounts a handler.
+	Mount bool
+}
+
+// hooks holds the lifecycle hooks of a router.
+type hooks struct {
+	routeRegistered []func(RouteInfo)
+	requestStart    []func(*http.Request)
+	requestEnd      []func(r *http.Request, status, size int, duration time.Duration)
+}
+
+// OnRouteRegistered adds a hook called for every route registered afterwards in the router, its sub-routers and groups.
+// The hook may panic to reject the route.
+func (m *Mux) OnRouteRegistered(fn func(RouteInfo)) {
+	root := m.root()
+	root.hooks.routeRegistered = append(root.hooks.routeRegistered, fn)
+}
+
+// OnRequestStart adds a hook called before the router handles a request.
+func (m *Mux) OnRequestStart(fn func(r *http.Request)) {
+	root := m.root()
+	root.hooks.requestStart = append(root.hooks.requestStart, fn)
+}
+
+// OnRequestEnd adds a hook called after the router has handled a request.
+func (m *Mux) OnRequestEnd(fn func(r *http.Request, status, size int, duration time.Duration)) {
+	root := m.root()
+	root.hooks.requestEnd = append(root.hooks.requestEnd, fn)
+}
+
+// registered calls the route registration hooks.
+func (h *hooks) registered(info RouteInfo) {
+	for _, fn := range h.routeRegistered {
+		fn(info)
+	}
+}
+
+// serve calls the request hooks around the given handler.
+func (h *hooks) serve(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	if len(h.requestStart) == 0 && len(h.requestEnd) == 0 {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	for _, fn := range h.requestStart {
+		fn(r)
+	}
+
+	start := time.Now()
+	sw := &statusResponseWriter{ResponseWriter: w}
+
+	defer func() {
+		duration := time.Since(start)
+
+		for _, fn := range h.requestEnd {
+			fn(r, sw.StatusCode(), sw.size, duration)
+		}
+	}()
+
+	next.ServeHTTP(sw, r)
+}
+
+// statusResponseWriter is a response writer that records the status code and the size of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int
+}
+
+// WriteHeader records the status code and writes it to the underlying response writer.
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the size and writes the given bytes to the underlying response writer.
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+
+	return n, err
+}
+
+// Flush flushes the underlying response writer if it supports it.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// StatusCode returns the status code of the response.
+func (w *statusResponseWriter) StatusCode() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return w.statusCode
+}
@@ -0,0 +1,108 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMux_OnRouteRegistered(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	routes := []RouteInfo{}
+
+	mux := NewMux()
+	mux.OnRouteRegistered(func(info RouteInfo) {
+		routes = append(routes, info)
+	})
+
+	mux.Get("/{$}", handler, WithName("home"))
+	mux.Route("/posts", func(r Router) {
+		r.Post("/{$}", handler, WithName("create-post"))
+		r.Group(func(r Router) {
+			r.Delete("/{id}", handler)
+		})
+	})
+	mux.Mount("/admin", http.NotFoundHandler())
+
+	expected := []RouteInfo{
+		{Name: "home", Method: http.MethodGet, Path: "/{$}"},
+		{Name: "create-post", Method: http.MethodPost, Path: "/posts/{$}"},
+		{Method: http.MethodDelete, Path: "/posts/{id}"},
+		{Path: "/admin/", Mount: true},
+	}
+
+	if len(routes) != len(expected) {
+		t.Fatalf("expected %d routes, got %d", len(expected), len(routes))
+	}
+
+	for i, route := range routes {
+		if route != expected[i] {
+			t.Errorf("unexpected route at %d: got=%+v, want=%+v", i, route, expected[i])
+		}
+	}
+}
+
+func TestMux_OnRouteRegisteredPolicy(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on unnamed POST route")
+		}
+	}()
+
+	mux := NewMux()
+	mux.OnRouteRegistered(func(info RouteInfo) {
+		if info.Method == http.MethodPost && info.Name == "" {
+			panic("POST routes must be named")
+		}
+	})
+
+	mux.Route("/posts", func(r Router) {
+		r.Post("/{$}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+}
+
+func TestMux_OnRequest(t *testing.T) {
+	var (
+		started  []string
+		statuses []int
+		sizes    []int
+	)
+
+	mux := NewMux()
+	mux.OnRequestStart(func(r *http.Request) {
+		started = append(started, r.URL.Path)
+	})
+
+	mux.OnRequestEnd(func(r *http.Request, status, size int, duration time.Duration) {
+		statuses = append(statuses, status)
+		sizes = append(sizes, size)
+	})
+
+	mux.Route("/posts", func(r Router) {
+		r.Post("/{$}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		})
+	})
+
+	paths := []string{"/posts/", "/unknown"}
+
+	for _, path := range paths {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+	}
+
+	if len(started) != 2 || started[0] != "/posts/" || started[1] != "/unknown" {
+		t.Fatalf("unexpected started requests: %v", started)
+	}
+	if len(statuses) != 2 || statuses[0] != http.StatusCreated || statuses[1] != http.StatusNotFound {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if sizes[0] != len("created") {
+		t.Fatalf("unexpected size: got=%d, want=%d", sizes[0], len("created"))
+	}
+}
@@ -0,0 +1,8 @@
+// Package jsonrpc provides a JSON-RPC 2.0 handler for the ki router.
+//
+// The methods are registered by name with typed params and the server is mounted on any path, e.g.
+//
+//	server := jsonrpc.NewServer()
+//	jsonrpc.Register(server, "sum", func(ctx context.Context, params []int) (int, error) { ... })
+//	router.Post("/rpc", server.ServeHTTP)
+package jsonrpc
@@ -0,0 +1,19 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	err := NewError(CodeInvalidParams, "Invalid params", nil)
+
+	if got := err.Error(); got != "jsonrpc error -32602: Invalid params" {
+		t.Fatalf("Unexpected message: got=%q", got)
+	}
+
+	body, _ := json.Marshal(err)
+	if string(body) != `{"code":-32602,"message":"Invalid params"}` {
+		t.Fatalf("Unexpected body: got=%s", body)
+	}
+}
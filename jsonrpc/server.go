@@ -0,0 +1,241 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/throskam/ki"
+)
+
+// version is the supported version of the protocol.
+const version = "2.0"
+
+// method is a registered method.
+type method func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server is a JSON-RPC 2.0 handler dispatching the calls to the registered methods.
+type Server struct {
+	mu      sync.RWMutex
+	methods map[string]method
+}
+
+// request is a JSON-RPC request object.
+// A request without id is a notification.
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// response is a JSON-RPC response object.
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// NewServer returns a new Server.
+func NewServer() *Server {
+	return &Server{
+		methods: map[string]method{},
+	}
+}
+
+// Register registers the function as the method with the given name.
+// The params are decoded from either a JSON object or array into P.
+// It panics if the name is reserved or already registered.
+func Register[P, R any](s *Server, name string, fn func(ctx context.Context, params P) (R, error)) {
+	if strings.HasPrefix(name, "rpc.") {
+		panic(fmt.Sprintf("method name %q is reserved", name))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.methods[name]; ok {
+		panic(fmt.Sprintf("method %q already registered", name))
+	}
+
+	s.methods[name] = func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var params P
+
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				return nil, NewError(CodeInvalidParams, "Invalid params", err.Error())
+			}
+		}
+
+		return fn(ctx, params)
+	}
+}
+
+// ServeHTTP handles a single or a batch call.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if !json.Valid(body) {
+		writeResponse(w, errorResponse(nil, NewError(CodeParseError, "Parse error", nil)))
+		return
+	}
+
+	if body[0] != '[' {
+		res := s.call(r.Context(), body)
+		if res == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeResponse(w, res)
+
+		return
+	}
+
+	var batch []json.RawMessage
+
+	_ = json.Unmarshal(body, &batch)
+
+	if len(batch) == 0 {
+		writeResponse(w, errorResponse(nil, NewError(CodeInvalidRequest, "Invalid Request", nil)))
+		return
+	}
+
+	responses := []*response{}
+
+	for _, raw := range batch {
+		if res := s.call(r.Context(), raw); res != nil {
+			responses = append(responses, res)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeResponse(w, responses)
+}
+
+// call calls the method of the raw request and returns the response, or nil for a notification.
+func (s *Server) call(ctx context.Context, raw json.RawMessage) *response {
+	var req request
+
+	if err := json.Unmarshal(raw, &req); err != nil || req.Version != version || req.Method == "" || !validID(req.ID) {
+		return errorResponse(nil, NewError(CodeInvalidRequest, "Invalid Request", nil))
+	}
+
+	notification := req.ID == nil
+
+	s.mu.RLock()
+	fn, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+
+	if !ok {
+		if notification {
+			return nil
+		}
+
+		return errorResponse(req.ID, NewError(CodeMethodNotFound, "Method not found", nil))
+	}
+
+	logger := ki.GetLogger(ctx).With(slog.String("rpcMethod", req.Method))
+
+	ctx = ki.SetLogger(ctx, logger)
+
+	start := time.Now()
+
+	result, err := fn(ctx, req.Params)
+
+	res := &response{
+		Version: version,
+		ID:      req.ID,
+	}
+
+	if err == nil {
+		res.Result, err = json.Marshal(result)
+	}
+
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = NewError(CodeInternalError, "Internal error", nil)
+		}
+
+		res.Error = rpcErr
+		res.Result = nil
+	}
+
+	attrs := []slog.Attr{
+		slog.String("id", string(req.ID)),
+		slog.Bool("notification", notification),
+		slog.Int64("duration", time.Since(start).Microseconds()),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Int("code", res.Error.Code), slog.String("error", err.Error()))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "rpc", attrs...)
+
+	if notification {
+		return nil
+	}
+
+	return res
+}
+
+// validID returns true if the id is absent, a string, a number or null.
+func validID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
+
+// errorResponse returns a new error response.
+func errorResponse(id json.RawMessage, err *Error) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	return &response{
+		Version: version,
+		Error:   err,
+		ID:      id,
+	}
+}
+
+// writeResponse writes the JSON response.
+func writeResponse(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,267 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/throskam/ki"
+)
+
+type subtractParams struct {
+	Minuend    int `json:"minuend"`
+	Subtrahend int `json:"subtrahend"`
+}
+
+func newTestServer() *Server {
+	server := NewServer()
+
+	Register(server, "sum", func(ctx context.Context, params []int) (int, error) {
+		sum := 0
+		for _, v := range params {
+			sum += v
+		}
+
+		return sum, nil
+	})
+
+	Register(server, "subtract", func(ctx context.Context, params subtractParams) (int, error) {
+		return params.Minuend - params.Subtrahend, nil
+	})
+
+	Register(server, "notify", func(ctx context.Context, params []int) (any, error) {
+		return nil, nil
+	})
+
+	Register(server, "request-id", func(ctx context.Context, params any) (string, error) {
+		return ki.GetRequestID(ctx), nil
+	})
+
+	Register(server, "fail", func(ctx context.Context, params any) (any, error) {
+		return nil, NewError(42, "Custom failure", "details")
+	})
+
+	Register(server, "crash", func(ctx context.Context, params any) (any, error) {
+		return nil, errors.New("database is down")
+	})
+
+	return server
+}
+
+func TestServer(t *testing.T) {
+	server := newTestServer()
+
+	tests := []struct {
+		name     string
+		body     string
+		code     int
+		expected string
+	}{
+		{
+			name:     "Positional params",
+			body:     `{"jsonrpc":"2.0","method":"sum","params":[1,2,4],"id":1}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","result":7,"id":1}`,
+		},
+		{
+			name:     "Named params",
+			body:     `{"jsonrpc":"2.0","method":"subtract","params":{"minuend":42,"subtrahend":23},"id":"a"}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","result":19,"id":"a"}`,
+		},
+		{
+			name:     "Null result",
+			body:     `{"jsonrpc":"2.0","method":"notify","id":2}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","result":null,"id":2}`,
+		},
+		{
+			name: "Notification",
+			body: `{"jsonrpc":"2.0","method":"notify","params":[1]}`,
+			code: http.StatusNoContent,
+		},
+		{
+			name:     "Request ID",
+			body:     `{"jsonrpc":"2.0","method":"request-id","id":3}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","result":"req-1","id":3}`,
+		},
+		{
+			name:     "Custom error",
+			body:     `{"jsonrpc":"2.0","method":"fail","id":4}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":42,"message":"Custom failure","data":"details"},"id":4}`,
+		},
+		{
+			name:     "Internal error",
+			body:     `{"jsonrpc":"2.0","method":"crash","id":5}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":5}`,
+		},
+		{
+			name:     "Method not found",
+			body:     `{"jsonrpc":"2.0","method":"foobar","id":"1"}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":"1"}`,
+		},
+		{
+			name:     "Invalid params",
+			body:     `{"jsonrpc":"2.0","method":"sum","params":{"a":1},"id":6}`,
+			code:     http.StatusOK,
+			expected: `"error":{"code":-32602,"message":"Invalid params"`,
+		},
+		{
+			name:     "Parse error",
+			body:     `{"jsonrpc":"2.0","method":"foobar,"params":"bar","baz]`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`,
+		},
+		{
+			name:     "Invalid request",
+			body:     `{"jsonrpc":"2.0","method":1,"params":"bar"}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "Invalid version",
+			body:     `{"jsonrpc":"1.0","method":"sum","id":1}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "Invalid id",
+			body:     `{"jsonrpc":"2.0","method":"sum","id":{}}`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "Empty batch",
+			body:     `[]`,
+			code:     http.StatusOK,
+			expected: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			name:     "Invalid batch",
+			body:     `[1,2]`,
+			code:     http.StatusOK,
+			expected: `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}]`,
+		},
+		{
+			name: "Batch",
+			body: `[
+				{"jsonrpc":"2.0","method":"sum","params":[1,2],"id":"1"},
+				{"jsonrpc":"2.0","method":"notify","params":[7]},
+				{"jsonrpc":"2.0","method":"subtract","params":{"minuend":42,"subtrahend":23},"id":"2"},
+				{"foo":"boo"},
+				{"jsonrpc":"2.0","method":"foo.get","id":"5"}
+			]`,
+			code:     http.StatusOK,
+			expected: `[{"jsonrpc":"2.0","result":3,"id":"1"},{"jsonrpc":"2.0","result":19,"id":"2"},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":"5"}]`,
+		},
+		{
+			name: "Batch of notifications",
+			body: `[{"jsonrpc":"2.0","method":"notify","params":[1]},{"jsonrpc":"2.0","method":"unknown"}]`,
+			code: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req = req.WithContext(ki.SetRequestID(req.Context(), "req-1"))
+			rec := httptest.NewRecorder()
+
+			server.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, rec.Code)
+			}
+
+			body := strings.TrimSpace(rec.Body.String())
+
+			if tt.expected == "" && body != "" {
+				t.Fatalf("expected empty body, got %q", body)
+			}
+			if !strings.Contains(body, tt.expected) {
+				t.Fatalf("expected body %q, got %q", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestServer_Method(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/rpc", nil)
+	rec := httptest.NewRecorder()
+
+	newTestServer().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != http.MethodPost {
+		t.Fatalf("expected Allow POST, got %q", got)
+	}
+}
+
+func TestServer_Logger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With(slog.String("requestID", "req-1"))
+
+	var methodLogger *slog.Logger
+
+	server := NewServer()
+	Register(server, "log", func(ctx context.Context, params any) (any, error) {
+		methodLogger = ki.MustGetLogger(ctx)
+		methodLogger.Info("inside")
+
+		return nil, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`[{"jsonrpc":"2.0","method":"log","id":1},{"jsonrpc":"2.0","method":"log"}]`))
+	req = req.WithContext(ki.SetLogger(req.Context(), logger))
+
+	server.ServeHTTP(httptest.NewRecorder(), req)
+
+	if methodLogger == nil {
+		t.Fatal("expected logger in method context")
+	}
+
+	output := buf.String()
+
+	if strings.Count(output, "msg=rpc") != 2 {
+		t.Fatalf("expected one log per invocation, got %q", output)
+	}
+	if !strings.Contains(output, `msg=inside requestID=req-1 rpcMethod=log`) {
+		t.Fatalf("expected method log with request attributes, got %q", output)
+	}
+	if !strings.Contains(output, "notification=true") {
+		t.Fatalf("expected notification log, got %q", output)
+	}
+}
+
+func TestRegister_Panics(t *testing.T) {
+	tests := map[string]string{
+		"Reserved":  "rpc.discover",
+		"Duplicate": "sum",
+	}
+
+	for name, method := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic for method %q", method)
+				}
+			}()
+
+			Register(newTestServer(), method, func(ctx context.Context, params any) (any, error) {
+				return nil, nil
+			})
+		})
+	}
+}
@@ -10,14 +10,23 @@ type Router interface {
 	ServeHTTP(w http.ResponseWriter, req *http.Request)
 
 	// Mount mounts the given handler at the given prefix.
-	Mount(prefix string, handler http.Handler)
+	Mount(prefix string, handler http.Handler, options ...RouterOption)
 
 	// Route creates a new router with the given prefix.
-	Route(prefix string, fn func(Router)) Router
+	Route(prefix string, fn func(Router), options ...RouterOption) Router
+
+	// Resource creates a new router with the given prefix and adds the RESTful routes implemented by the controller.
+	Resource(prefix string, controller any, fn func(Router)) Router
+
+	// Service creates a new router with the given prefix exposing the RPC methods of the service.
+	Service(prefix string, service any, options ...RouteOption) Router
+
+	// Version creates a new router serving the given version of the API.
+	Version(version string, fn func(Router)) Router
 
 	// Group creates a new router without any prefix.
 	// It is useful for adding middlewares to a group of routes.
-	Group(fn func(Router)) Router
+	Group(fn func(Router), options ...RouterOption) Router
 
 	// Use adds the given middlewares to the router.
 	Use(middlewares ...func(http.Handler) http.Handler)
@@ -34,9 +43,12 @@ type Router interface {
 
 	// Registry returns the registry of the router.
 	Registry() *Registry
+
+	// Container returns the service container of the router.
+	Container() *Container
 }
 
 // NewRouter returns a new Router.
-func NewRouter() *Mux {
-	return NewMux()
+func NewRouter(options ...MuxOption) *Mux {
+	return NewMux(options...)
 }
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"maps"
 	"net/url"
-	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -14,6 +14,7 @@ type Location struct {
 	method     string
 	pattern    string
 	pathParams []string
+	params     map[string]string
 	query      url.Values
 }
 
@@ -38,27 +39,96 @@ func (l Location) Pattern() string {
 }
 
 // URL returns the parameterized URL.
+// The missing path parameters are left as placeholders and the extra ones are ignored, see URLE.
 // It panics if the URL is invalid.
 func (l Location) URL() *url.URL {
-	path := l.pattern
-
-	if l.prefix != "" {
-		path = l.prefix + path
+	u, err := l.build(false)
+	if err != nil {
+		panic(err.Error())
 	}
 
-	path = strings.ReplaceAll(path, "{$}", "")
+	return u
+}
+
+// URLE returns the parameterized URL.
+// It returns an error if a path parameter is missing or unknown, or if the URL is invalid.
+func (l Location) URLE() (*url.URL, error) {
+	return l.build(true)
+}
+
+// build returns the parameterized URL, checking the path parameters if strict is true.
+//
+// Each placeholder takes the named parameter of the same name, see WithParams, or else the next positional parameter,
+// see WithPathParams. The values are escaped, the slashes of a "{name...}" wildcard being kept.
+func (l Location) build(strict bool) (*url.URL, error) {
+	segments := strings.Split(l.prefix+l.pattern, "/")
 
-	re := regexp.MustCompile(`\{[^}]+\}`)
 	index := 0
+	used := 0
+	missing := []string{}
+
+	for i, segment := range segments {
+		if segment == "{$}" {
+			segments[i] = ""
+			continue
+		}
+
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(segment[1:len(segment)-1], ":")
+		name, rest := strings.CutSuffix(name, "...")
+
+		value, ok := l.params[name]
 
-	path = re.ReplaceAllStringFunc(path, func(match string) string {
-		if index < len(l.pathParams) {
-			replacement := l.pathParams[index]
+		switch {
+		case ok:
+			used++
+		case index < len(l.pathParams):
+			value = l.pathParams[index]
 			index++
-			return replacement
+		default:
+			missing = append(missing, name)
+			continue
+		}
+
+		if rest {
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+
+			segments[i] = strings.Join(parts, "/")
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+	}
+
+	if strict {
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("missing path parameters %v for %s", missing, l.prefix+l.pattern)
+		}
+
+		if extra := len(l.pathParams) - index; extra > 0 {
+			return nil, fmt.Errorf("%d extra path parameters for %s", extra, l.prefix+l.pattern)
+		}
+
+		if used < len(l.params) {
+			placeholders := l.placeholders()
+			unknown := []string{}
+
+			for _, name := range slices.Sorted(maps.Keys(l.params)) {
+				if !slices.Contains(placeholders, name) {
+					unknown = append(unknown, name)
+				}
+			}
+
+			return nil, fmt.Errorf("unknown path parameters %v for %s", unknown, l.prefix+l.pattern)
 		}
-		return match
-	})
+	}
+
+	path := strings.Join(segments, "/")
 
 	if len(l.query) > 0 {
 		path = path + "?" + l.query.Encode()
@@ -66,10 +136,27 @@ func (l Location) URL() *url.URL {
 
 	u, err := url.Parse(path)
 	if err != nil {
-		panic(fmt.Sprintf("cannot parse URL (%v)", path))
+		return nil, fmt.Errorf("cannot parse URL (%v)", path)
 	}
 
-	return u
+	return u, nil
+}
+
+// placeholders returns the names of the placeholders of the pattern, the "{$}" excluded.
+func (l Location) placeholders() []string {
+	names := []string{}
+
+	for _, segment := range strings.Split(l.prefix+l.pattern, "/") {
+		if segment == "{$}" || !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(segment[1:len(segment)-1], ":")
+
+		names = append(names, strings.TrimSuffix(name, "..."))
+	}
+
+	return names
 }
 
 // WithPrefix returns a new Location with the prefix.
@@ -86,6 +173,14 @@ func (l Location) WithPathParams(params ...string) Location {
 	return l
 }
 
+// WithParams returns a new Location with the named path parameters, e.g. {"id": "1"} for "/posts/{id}".
+// The named parameters take precedence over the positional ones, which fill the remaining placeholders in order.
+func (l Location) WithParams(params map[string]string) Location {
+	l.params = maps.Clone(params)
+
+	return l
+}
+
 // WithQuery returns a new Location with the query.
 func (l Location) WithQuery(query url.Values) Location {
 	l.query = query
@@ -110,3 +110,103 @@ func TestLocation_URLParsingError(t *testing.T) {
 	loc := NewLocation(http.MethodGet, badPattern)
 	loc.URL()
 }
+
+func TestLocation_WithParams(t *testing.T) {
+	loc := NewLocation(http.MethodGet, "/users/{userID}/posts/{postID}").
+		WithParams(map[string]string{"postID": "99"}).
+		WithPathParams("42")
+
+	u, err := loc.URLE()
+	if err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	if u.Path != "/users/42/posts/99" {
+		t.Fatalf("Unexpected path: got=%s, want=%s", u.Path, "/users/42/posts/99")
+	}
+}
+
+func TestLocation_Escaping(t *testing.T) {
+	tests := []struct {
+		name     string
+		location Location
+		expected string
+	}{
+		{
+			name:     "Segment",
+			location: NewLocation(http.MethodGet, "/posts/{slug}").WithPathParams("a/b?c#d e"),
+			expected: "/posts/a%2Fb%3Fc%23d%20e",
+		},
+		{
+			name:     "Wildcard",
+			location: NewLocation(http.MethodGet, "/files/{path...}").WithPathParams("docs/a b.txt"),
+			expected: "/files/docs/a%20b.txt",
+		},
+		{
+			name:     "Constraint",
+			location: NewLocation(http.MethodGet, "/posts/{id:[0-9]+}/{$}").WithParams(map[string]string{"id": "1"}),
+			expected: "/posts/1/",
+		},
+		{
+			name:     "Query",
+			location: NewLocation(http.MethodGet, "/search").WithQueryParam("q", "a&b"),
+			expected: "/search?q=a%26b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := tt.location.URLE()
+			if err != nil {
+				t.Fatalf("Unexpected error: got=%v", err)
+			}
+
+			if u.String() != tt.expected {
+				t.Fatalf("Unexpected URL: got=%s, want=%s", u.String(), tt.expected)
+			}
+		})
+	}
+
+	u := NewLocation(http.MethodGet, "/posts/{slug}").WithPathParams("a/b").URL()
+
+	if u.Path != "/posts/a/b" || u.EscapedPath() != "/posts/a%2Fb" {
+		t.Fatalf("Unexpected path: got=%s, want=%s", u.EscapedPath(), "/posts/a%2Fb")
+	}
+}
+
+func TestLocation_URLE(t *testing.T) {
+	tests := []struct {
+		name     string
+		location Location
+		expected string
+	}{
+		{
+			name:     "Missing",
+			location: NewLocation(http.MethodGet, "/teams/{id}/members/{mid}").WithPathParams("10"),
+			expected: "missing path parameters [mid] for /teams/{id}/members/{mid}",
+		},
+		{
+			name:     "Extra",
+			location: NewLocation(http.MethodGet, "/projects/{id}").WithPathParams("1", "2"),
+			expected: "1 extra path parameters for /projects/{id}",
+		},
+		{
+			name:     "Unknown",
+			location: NewLocation(http.MethodGet, "/projects/{id}").WithParams(map[string]string{"id": "1", "slug": "a"}),
+			expected: "unknown path parameters [slug] for /projects/{id}",
+		},
+		{
+			name:     "Invalid",
+			location: NewLocation(http.MethodGet, "://bad-url"),
+			expected: "cannot parse URL (://bad-url)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.location.URLE(); err == nil || err.Error() != tt.expected {
+				t.Fatalf("Unexpected error: got=%v, want=%s", err, tt.expected)
+			}
+		})
+	}
+}
@@ -42,4 +42,16 @@ func TestSetLoggerLevelByText_InvalidLevel(t *testing.T) {
 	}()
 
 	SetLoggerLevelByText("invalid-level")
-}
\ No newline at end of file
+}
+
+func TestGetLogger(t *testing.T) {
+	if got := GetLogger(context.Background()); got != Logger {
+		t.Error("expected global logger without logger in context")
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+
+	if got := GetLogger(SetLogger(context.Background(), logger)); got != logger {
+		t.Error("expected logger from context")
+	}
+}
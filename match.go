@@ -0,0 +1,130 @@
+package ki
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// routeVariants dispatches the requests of a pattern to the first variant matching the request.
+type routeVariants struct {
+	pattern  string
+	variants []routeVariant
+	// siblings holds the variants of the router by pattern, used to find the other methods of the path.
+	siblings map[string]*routeVariants
+}
+
+// routeVariant is a route sharing its pattern with other routes.
+type routeVariant struct {
+	matchers []func(*http.Request) bool
+	handler  http.Handler
+}
+
+// WithMatcher returns a new RouteOption that restricts the route to the requests accepted by the given function.
+// Several routes with the same pattern can be added with different matchers; they are tried in order and the
+// requests matching none of them get a 405 Method Not Allowed response if other methods are routed on the path,
+// a 404 Not Found response otherwise. They do not fall back to the less specific patterns.
+func WithMatcher(fn func(*http.Request) bool) RouteOption {
+	return func(rc *Route) {
+		rc.matchers = append(rc.matchers, fn)
+	}
+}
+
+// WithHeaderMatch returns a new RouteOption that restricts the route to the requests with the given header value.
+// The parameters of a media type are ignored, e.g. "application/json" matches "application/json; charset=utf-8".
+// An empty value matches any request with the header.
+func WithHeaderMatch(key, value string) RouteOption {
+	return WithMatcher(func(r *http.Request) bool {
+		values := r.Header.Values(key)
+
+		if value == "" {
+			return len(values) > 0
+		}
+
+		return slices.ContainsFunc(values, func(v string) bool {
+			mediaType, _, _ := strings.Cut(v, ";")
+
+			return strings.EqualFold(v, value) || strings.EqualFold(strings.TrimSpace(mediaType), value)
+		})
+	})
+}
+
+// WithQueryMatch returns a new RouteOption that restricts the route to the requests with the given query value.
+// An empty value matches any request with the query parameter.
+func WithQueryMatch(key, value string) RouteOption {
+	return WithMatcher(func(r *http.Request) bool {
+		values, ok := r.URL.Query()[key]
+
+		if value == "" {
+			return ok
+		}
+
+		return slices.Contains(values, value)
+	})
+}
+
+// add adds a variant.
+// It panics if a previous variant matches every request, as the new one would be unreachable.
+func (v *routeVariants) add(matchers []func(*http.Request) bool, handler http.Handler) {
+	for _, variant := range v.variants {
+		if len(variant.matchers) == 0 {
+			panic(fmt.Sprintf("pattern %q is already registered without matcher", v.pattern))
+		}
+	}
+
+	v.variants = append(v.variants, routeVariant{
+		matchers: matchers,
+		handler:  handler,
+	})
+}
+
+// ServeHTTP dispatches the request to the first matching variant.
+func (v *routeVariants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, variant := range v.variants {
+		if variant.match(r) {
+			variant.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	if allowed := v.allowed(); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// allowed returns the sorted methods of the other patterns with the same path.
+func (v *routeVariants) allowed() []string {
+	method, path, ok := strings.Cut(v.pattern, " ")
+	if !ok {
+		return nil
+	}
+
+	allowed := []string{}
+
+	for pattern := range v.siblings {
+		m, p, ok := strings.Cut(pattern, " ")
+		if ok && p == path && m != method {
+			allowed = append(allowed, m)
+		}
+	}
+
+	slices.Sort(allowed)
+
+	return allowed
+}
+
+// match returns true if every matcher accepts the request.
+func (v routeVariant) match(r *http.Request) bool {
+	for _, fn := range v.matchers {
+		if !fn(r) {
+			return false
+		}
+	}
+
+	return true
+}
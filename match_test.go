@@ -0,0 +1,119 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMux_RouteMatchers(t *testing.T) {
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux(option)
+
+			reply := func(body string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(body))
+				}
+			}
+
+			mux.Route("/api", func(r Router) {
+				r.Post("/posts", reply("json"), WithHeaderMatch("Content-Type", "application/json"), WithName("create-post-json"))
+				r.Post("/posts", reply("form"), WithHeaderMatch("Content-Type", "application/x-www-form-urlencoded"))
+				r.Get("/search", reply("legacy"), WithQueryMatch("legacy", ""))
+				r.Get("/search", reply("v2"), WithQueryMatch("v", "2"), WithName("search-v2"))
+				r.Get("/search", reply("mobile"), WithMatcher(func(r *http.Request) bool {
+					return strings.HasPrefix(r.Header.Get("X-Client"), "mobile")
+				}))
+				r.Get("/search", reply("default"))
+			})
+
+			tests := []struct {
+				method  string
+				path    string
+				headers map[string]string
+				code    int
+				body    string
+			}{
+				{method: http.MethodPost, path: "/api/posts", headers: map[string]string{"Content-Type": "application/json; charset=utf-8"}, code: http.StatusOK, body: "json"},
+				{method: http.MethodPost, path: "/api/posts", headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, code: http.StatusOK, body: "form"},
+				{method: http.MethodPost, path: "/api/posts", headers: map[string]string{"Content-Type": "text/plain"}, code: http.StatusNotFound},
+				{method: http.MethodPut, path: "/api/posts", code: http.StatusMethodNotAllowed},
+				{method: http.MethodGet, path: "/api/search?legacy", code: http.StatusOK, body: "legacy"},
+				{method: http.MethodGet, path: "/api/search?v=2", code: http.StatusOK, body: "v2"},
+				{method: http.MethodGet, path: "/api/search?v=3", code: http.StatusOK, body: "default"},
+				{method: http.MethodGet, path: "/api/search", headers: map[string]string{"X-Client": "mobile/1.2"}, code: http.StatusOK, body: "mobile"},
+				{method: http.MethodGet, path: "/api/search?legacy&v=2", code: http.StatusOK, body: "legacy"},
+			}
+
+			for _, tt := range tests {
+				req := httptest.NewRequest(tt.method, tt.path, nil)
+				for key, value := range tt.headers {
+					req.Header.Set(key, value)
+				}
+
+				rec := httptest.NewRecorder()
+
+				mux.ServeHTTP(rec, req)
+
+				if rec.Code != tt.code {
+					t.Fatalf("Unexpected status for %s %s: got=%d, want=%d", tt.method, tt.path, rec.Code, tt.code)
+				}
+				if tt.body != "" && rec.Body.String() != tt.body {
+					t.Fatalf("Unexpected body for %s %s: got=%q, want=%q", tt.method, tt.path, rec.Body.String(), tt.body)
+				}
+			}
+
+			if got := mux.Registry().Get("search-v2").URL().String(); got != "/api/search" {
+				t.Fatalf("Unexpected location: got=%s", got)
+			}
+		})
+	}
+}
+
+func TestMux_RouteMatchers_Unreachable(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for variant after a route without matcher")
+		}
+	}()
+
+	mux := NewMux()
+	mux.Get("/search", func(w http.ResponseWriter, r *http.Request) {})
+	mux.Get("/search", func(w http.ResponseWriter, r *http.Request) {}, WithQueryMatch("v", "2"))
+}
+
+func TestMux_RouteMatchers_MethodNotAllowed(t *testing.T) {
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux(option)
+
+			handler := func(w http.ResponseWriter, r *http.Request) {}
+
+			mux.Get("/x", handler, WithHeaderMatch("X-Client", "mobile"))
+			mux.Post("/x", handler)
+			mux.Delete("/x", handler)
+			mux.Get("/y", handler, WithHeaderMatch("X-Client", "mobile"))
+
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, http.StatusMethodNotAllowed)
+			}
+			if got := rec.Header().Get("Allow"); got != "DELETE, POST" {
+				t.Fatalf("Unexpected allow header: got=%q, want=%q", got, "DELETE, POST")
+			}
+
+			rec = httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/y", nil))
+
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package ki
+
+import (
+	"net/http"
+)
+
+// matcher matches the requests against the registered patterns.
+type matcher interface {
+	http.Handler
+
+	// Handle registers the handler for the given pattern.
+	Handle(pattern string, handler http.Handler)
+
+	// Handler returns the handler and the pattern matching the request.
+	Handler(r *http.Request) (h http.Handler, pattern string)
+}
+
+// WithServeMux returns a new MuxOption that matches the requests with a ServeMux.
+// Every sub-router gets its own ServeMux and the prefix is stripped from the request path.
+// This is the default.
+func WithServeMux() MuxOption {
+	return func(m *Mux) {
+		m.mux = http.NewServeMux()
+		m.flat = false
+	}
+}
+
+// WithRadixTree returns a new MuxOption that matches the requests with a radix tree.
+// The routes of every sub-router and group are flattened into a single tree, so the request path is never stripped
+// except for the handlers attached with Mount.
+// Wildcards accept a regular expression constraint, e.g. "/posts/{id:[0-9]+}".
+func WithRadixTree() MuxOption {
+	return func(m *Mux) {
+		m.mux = newRadixMatcher()
+		m.flat = true
+	}
+}
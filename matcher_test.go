@@ -0,0 +1,160 @@
+package ki
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMux_Matchers(t *testing.T) {
+	options := map[string]MuxOption{
+		"ServeMux":  WithServeMux(),
+		"RadixTree": WithRadixTree(),
+	}
+
+	for engine, option := range options {
+		t.Run(engine, func(t *testing.T) {
+			var count int
+
+			handler := func(body string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(body + r.PathValue("postID") + r.PathValue("id")))
+				}
+			}
+
+			mw := func(n int) func(http.Handler) http.Handler {
+				return func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						count += n
+						next.ServeHTTP(w, r)
+					})
+				}
+			}
+
+			admin := NewMux()
+			admin.Get("/dashboard", handler("dashboard"))
+
+			mux := NewMux(option)
+			mux.Use(mw(1))
+			mux.Get("/{$}", handler("home"))
+			mux.Route("/posts", func(r Router) {
+				r.Use(mw(2))
+				r.Get("/{$}", handler("posts"))
+				r.Group(func(r Router) {
+					r.Use(mw(4))
+					r.Get("/{id}", handler("post"))
+				})
+				r.Route("/{postID}/comments", func(r Router) {
+					r.Get("/{id}", handler("comment"))
+				})
+			})
+			mux.Mount("/admin", admin)
+
+			tests := []struct {
+				path  string
+				body  string
+				count int
+			}{
+				{path: "/", body: "home", count: 1},
+				{path: "/posts/", body: "posts", count: 3},
+				{path: "/posts/1", body: "post1", count: 7},
+				{path: "/posts/1/comments/2", body: "comment12", count: 3},
+				{path: "/admin/dashboard", body: "dashboard", count: 1},
+			}
+
+			for _, tt := range tests {
+				count = 0
+
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				rec := httptest.NewRecorder()
+
+				mux.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Unexpected status for %s: got=%d", tt.path, rec.Code)
+				}
+				if rec.Body.String() != tt.body {
+					t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.body)
+				}
+				if count != tt.count {
+					t.Fatalf("Wrong middleware count for %s: got=%d, want=%d", tt.path, count, tt.count)
+				}
+			}
+
+			if got := mux.Registry(); got == nil {
+				t.Fatal("Missing registry")
+			}
+		})
+	}
+}
+
+func TestMux_RadixTreeConstraints(t *testing.T) {
+	handler := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body + " " + r.PathValue("id")))
+		}
+	}
+
+	mux := NewMux(WithRadixTree())
+	mux.Route("/posts", func(r Router) {
+		r.Get("/{id:[0-9]+}", handler("by id"))
+		r.Get("/{slug}", handler("by slug"))
+	})
+
+	tests := map[string]string{
+		"/posts/42":    "by id 42",
+		"/posts/hello": "by slug ",
+	}
+
+	for path, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		if rec.Body.String() != expected {
+			t.Fatalf("Unexpected body for %s: got=%q, want=%q", path, rec.Body.String(), expected)
+		}
+	}
+}
+
+func benchmarkMux(b *testing.B, option MuxOption) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	mux := NewMux(option)
+
+	var route func(r Router, depth int)
+	route = func(r Router, depth int) {
+		for i := range 10 {
+			r.Get(fmt.Sprintf("/static%d", i), handler)
+			r.Get(fmt.Sprintf("/param%d/{id}", i), handler)
+		}
+
+		if depth > 0 {
+			r.Route("/level", func(r Router) {
+				route(r, depth-1)
+			})
+		}
+	}
+
+	route(mux, 5)
+
+	req := httptest.NewRequest(http.MethodGet, "/level/level/level/level/level/param9/42", nil)
+	rec := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		mux.ServeHTTP(rec, req)
+	}
+}
+
+func BenchmarkMux_ServeMux(b *testing.B) {
+	benchmarkMux(b, WithServeMux())
+}
+
+func BenchmarkMux_RadixTree(b *testing.B) {
+	benchmarkMux(b, WithRadixTree())
+}
@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/throskam/ki"
+)
+
+// BaseURLOptions configures the BaseURL middleware.
+type BaseURLOptions struct {
+	// Base is the public base URL, e.g. "https://example.com".
+	// If empty, the base URL is derived from the request.
+	Base string
+	// TrustedProxies are the IPs or CIDR networks of the proxies whose X-Forwarded-Proto and X-Forwarded-Host
+	// headers are honored when the base URL is derived from the request.
+	// They are matched against the remote address, so the middleware must run before RealIP.
+	TrustedProxies []string
+}
+
+// BaseURL returns a middleware that sets the base URL of the absolute URLs for the request, see ki.AbsoluteURL.
+// It panics if the base URL or a trusted proxy is invalid.
+func BaseURL(options BaseURLOptions) func(http.Handler) http.Handler {
+	middleware, err := newBaseURL(options)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return middleware
+}
+
+// newBaseURL returns the BaseURL middleware or an error if the options are invalid.
+func newBaseURL(options BaseURLOptions) (func(http.Handler) http.Handler, error) {
+	var base *url.URL
+
+	if options.Base != "" {
+		u, err := url.Parse(options.Base)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid base URL %s", options.Base)
+		}
+
+		base = u
+	}
+
+	networks, err := parseNetworks(options.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies (%w)", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u := base
+
+			if u == nil {
+				u = getBaseURL(r, containsRemoteAddr(networks, r.RemoteAddr))
+			}
+
+			next.ServeHTTP(w, r.WithContext(ki.SetBaseURL(r.Context(), u)))
+		})
+	}, nil
+}
+
+// getBaseURL returns the base URL of the request, honoring the X-Forwarded-Proto and X-Forwarded-Host headers if
+// trusted is true.
+func getBaseURL(r *http.Request, trusted bool) *url.URL {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	host := r.Host
+
+	if trusted {
+		// The headers contain a list of values separated by commas when several proxies are chained.
+		proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+
+		if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+
+		if forwarded, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Host"), ","); forwarded != "" {
+			host = strings.TrimSpace(forwarded)
+		}
+	}
+
+	return &url.URL{Scheme: scheme, Host: host}
+}
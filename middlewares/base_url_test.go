@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/throskam/ki"
+)
+
+func TestBaseURLMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  BaseURLOptions
+		remote   string
+		tls      bool
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "Configured",
+			options:  BaseURLOptions{Base: "https://example.com/app"},
+			headers:  map[string]string{"X-Forwarded-Host": "evil.com"},
+			expected: "https://example.com/app/posts/1",
+		},
+		{
+			name:     "Request",
+			expected: "http://example.com/posts/1",
+		},
+		{
+			name:     "TLS",
+			tls:      true,
+			expected: "https://example.com/posts/1",
+		},
+		{
+			name:     "Untrusted proxy",
+			options:  BaseURLOptions{TrustedProxies: []string{"10.0.0.1"}},
+			remote:   "192.0.2.1:1234",
+			headers:  map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.com"},
+			expected: "http://example.com/posts/1",
+		},
+		{
+			name:     "Trusted proxy",
+			options:  BaseURLOptions{TrustedProxies: []string{"10.0.0.0/8"}},
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Host": "www.example.com, proxy"},
+			expected: "https://www.example.com/posts/1",
+		},
+		{
+			name:     "Invalid forwarded scheme",
+			options:  BaseURLOptions{TrustedProxies: []string{"10.0.0.0/8"}},
+			remote:   "10.0.0.1:1234",
+			headers:  map[string]string{"X-Forwarded-Proto": "javascript"},
+			expected: "http://example.com/posts/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := ki.NewRouter()
+			router.Use(Locator(router), BaseURL(tt.options))
+			router.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+				u, err := ki.AbsoluteURL(r.Context(), "get-post", r.PathValue("id"))
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				_, _ = w.Write([]byte(u.String()))
+			}, ki.WithName("get-post"))
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+			if tt.remote != "" {
+				req.RemoteAddr = tt.remote
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.expected {
+				t.Fatalf("Unexpected URL: got=%s, want=%s", rec.Body.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBaseURLMiddleware_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for invalid base URL")
+		}
+	}()
+
+	BaseURL(BaseURLOptions{Base: "example.com"})
+}
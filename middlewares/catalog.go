@@ -0,0 +1,259 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/throskam/ki"
+	"golang.org/x/text/language"
+)
+
+// Catalog is a catalog of named middlewares.
+// It implements the ki.MiddlewareCatalog interface.
+type Catalog struct {
+	builders map[string]func(args json.RawMessage, router ki.Router) (func(http.Handler) http.Handler, error)
+}
+
+// Duration is a time.Duration encoded as a JSON string such as "5s".
+type Duration time.Duration
+
+// UnmarshalJSON decodes the duration from a JSON string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
+
+// MarshalJSON encodes the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// BaseURLConfig is the configuration of the BaseURL middleware.
+type BaseURLConfig struct {
+	Base           string   `json:"base"`
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+// ContentCharsetConfig is the configuration of the ContentCharset middleware.
+type ContentCharsetConfig struct {
+	Charsets []string `json:"charsets"`
+}
+
+// ContentEncodingConfig is the configuration of the ContentEncoding middleware.
+type ContentEncodingConfig struct {
+	Encodings []string `json:"encodings"`
+}
+
+// ContentTypeConfig is the configuration of the ContentType middleware.
+type ContentTypeConfig struct {
+	Types []string `json:"types"`
+}
+
+// ContentSecurityPolicyConfig is the configuration of the ContentSecurityPolicy middleware.
+type ContentSecurityPolicyConfig struct {
+	Policy map[string][]string `json:"policy"`
+}
+
+// LanguageConfig is the configuration of the Language middleware.
+type LanguageConfig struct {
+	Languages []string `json:"languages"`
+}
+
+// MaintenanceConfig is the configuration of the Maintenance middleware.
+// The maintenance mode is fixed by the configuration, use NewMaintenance to toggle it at runtime.
+type MaintenanceConfig struct {
+	Enabled      bool     `json:"enabled"`
+	Message      string   `json:"message"`
+	RetryAfter   Duration `json:"retryAfter"`
+	AllowedIPs   []string `json:"allowedIPs"`
+	BypassCookie string   `json:"bypassCookie"`
+	BypassToken  string   `json:"bypassToken"`
+	Paths        []string `json:"paths"`
+}
+
+// OverrideLanguageConfig is the configuration of the OverrideLanguage middleware.
+type OverrideLanguageConfig struct {
+	Cookie string `json:"cookie"`
+}
+
+// StripPrefixConfig is the configuration of the StripePrefix middleware.
+type StripPrefixConfig struct {
+	Prefix string `json:"prefix"`
+}
+
+// TimeoutConfig is the configuration of the Timeout middleware.
+type TimeoutConfig struct {
+	Duration Duration `json:"duration"`
+}
+
+// ValidSignatureConfig is the configuration of the ValidSignature middleware.
+type ValidSignatureConfig struct {
+	Keys []string `json:"keys"`
+}
+
+// NewCatalog returns a new empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		builders: map[string]func(json.RawMessage, ki.Router) (func(http.Handler) http.Handler, error){},
+	}
+}
+
+// DefaultCatalog returns a new Catalog with the middlewares of this package.
+func DefaultCatalog() *Catalog {
+	c := NewCatalog()
+
+	Register(c, "base_url", func(args BaseURLConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return newBaseURL(BaseURLOptions(args))
+	})
+
+	Register(c, "content_charset", func(args ContentCharsetConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return ContentCharset(args.Charsets...), nil
+	})
+
+	Register(c, "content_encoding", func(args ContentEncodingConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return ContentEncoding(args.Encodings...), nil
+	})
+
+	Register(c, "content_type", func(args ContentTypeConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return ContentType(args.Types...), nil
+	})
+
+	Register(c, "csp", func(args ContentSecurityPolicyConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return ContentSecurityPolicy(url.Values(args.Policy)), nil
+	})
+
+	Register(c, "language", func(args LanguageConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		tags := []language.Tag{}
+
+		for _, l := range args.Languages {
+			tag, err := language.Parse(l)
+			if err != nil {
+				return nil, err
+			}
+
+			tags = append(tags, tag)
+		}
+
+		return Language(tags...), nil
+	})
+
+	Register(c, "override_language", func(args OverrideLanguageConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return OverrideLanguage(args.Cookie), nil
+	})
+
+	Register(c, "locator", func(_ struct{}, router ki.Router) (func(http.Handler) http.Handler, error) {
+		return Locator(router), nil
+	})
+
+	Register(c, "maintenance", func(args MaintenanceConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		if _, err := parseNetworks(args.AllowedIPs); err != nil {
+			return nil, err
+		}
+
+		maintenance := NewMaintenance(MaintenanceOptions{
+			AllowedIPs:   args.AllowedIPs,
+			BypassCookie: args.BypassCookie,
+			BypassToken:  args.BypassToken,
+			Paths:        args.Paths,
+		})
+
+		if args.Enabled {
+			maintenance.Enable(args.Message, time.Duration(args.RetryAfter))
+		}
+
+		return maintenance.Middleware(), nil
+	})
+
+	Register(c, "no_cache", func(_ struct{}, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return NoCache(), nil
+	})
+
+	Register(c, "real_ip", func(_ struct{}, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return RealIP(), nil
+	})
+
+	Register(c, "recoverer", func(_ struct{}, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return Recoverer(), nil
+	})
+
+	Register(c, "request_id", func(_ struct{}, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return RequestID(), nil
+	})
+
+	Register(c, "request_logger", func(_ struct{}, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return RequestLogger(), nil
+	})
+
+	Register(c, "strip_prefix", func(args StripPrefixConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		return StripePrefix(args.Prefix), nil
+	})
+
+	Register(c, "timeout", func(args TimeoutConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		if args.Duration <= 0 {
+			return nil, fmt.Errorf("invalid duration %s", time.Duration(args.Duration))
+		}
+
+		return Timeout(time.Duration(args.Duration)), nil
+	})
+
+	Register(c, "valid_signature", func(args ValidSignatureConfig, _ ki.Router) (func(http.Handler) http.Handler, error) {
+		if len(args.Keys) == 0 {
+			return nil, errors.New("missing keys")
+		}
+
+		keys := make([][]byte, len(args.Keys))
+		for i, key := range args.Keys {
+			keys[i] = []byte(key)
+		}
+
+		return ValidSignature(keys...), nil
+	})
+
+	return c
+}
+
+// Register registers a middleware in the catalog.
+// The arguments of the middleware are decoded into T before calling build.
+func Register[T any](c *Catalog, name string, build func(args T, router ki.Router) (func(http.Handler) http.Handler, error)) {
+	c.builders[name] = func(raw json.RawMessage, router ki.Router) (func(http.Handler) http.Handler, error) {
+		var args T
+
+		if len(raw) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+
+			if err := decoder.Decode(&args); err != nil {
+				return nil, fmt.Errorf("cannot decode arguments: %w", err)
+			}
+		}
+
+		return build(args, router)
+	}
+}
+
+// Middleware returns the middleware registered with the given name configured with the given arguments.
+func (c *Catalog) Middleware(name string, args json.RawMessage, router ki.Router) (func(http.Handler) http.Handler, error) {
+	build, ok := c.builders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown middleware %q", name)
+	}
+
+	return build(args, router)
+}
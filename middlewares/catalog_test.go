@@ -0,0 +1,161 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/throskam/ki"
+)
+
+func TestCatalog_DefaultCatalog(t *testing.T) {
+	catalog := DefaultCatalog()
+	router := ki.NewMux()
+
+	tests := []struct {
+		name string
+		args string
+	}{
+		{name: "content_charset", args: `{"charsets": ["utf-8"]}`},
+		{name: "content_encoding", args: `{"encodings": ["gzip"]}`},
+		{name: "content_type", args: `{"types": ["application/json"]}`},
+		{name: "csp", args: `{"policy": {"default-src": ["'self'"]}}`},
+		{name: "language", args: `{"languages": ["en", "fr"]}`},
+		{name: "override_language", args: `{"cookie": "lang"}`},
+		{name: "locator"},
+		{name: "maintenance", args: `{"enabled": true, "retryAfter": "10m", "allowedIPs": ["10.0.0.0/8"], "paths": ["/health"]}`},
+		{name: "no_cache"},
+		{name: "real_ip"},
+		{name: "recoverer"},
+		{name: "request_id"},
+		{name: "request_logger"},
+		{name: "strip_prefix", args: `{"prefix": "/api"}`},
+		{name: "timeout", args: `{"duration": "5s"}`},
+		{name: "valid_signature", args: `{"keys": ["secret"]}`},
+		{name: "base_url", args: `{"base": "https://example.com", "trustedProxies": ["10.0.0.0/8"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args json.RawMessage
+			if tt.args != "" {
+				args = json.RawMessage(tt.args)
+			}
+
+			middleware, err := catalog.Middleware(tt.name, args, router)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if middleware == nil {
+				t.Fatal("expected middleware, got nil")
+			}
+		})
+	}
+}
+
+func TestCatalog_Errors(t *testing.T) {
+	catalog := DefaultCatalog()
+	router := ki.NewMux()
+
+	tests := []struct {
+		name string
+		args string
+	}{
+		{name: "unknown"},
+		{name: "timeout", args: `{"duration": "soon"}`},
+		{name: "timeout", args: `{"duration": "5s", "extra": true}`},
+		{name: "timeout"},
+		{name: "language", args: `{"languages": ["not a language"]}`},
+		{name: "valid_signature", args: `{"keys": []}`},
+		{name: "base_url", args: `{"base": "/relative"}`},
+		{name: "base_url", args: `{"trustedProxies": ["not an ip"]}`},
+		{name: "maintenance", args: `{"allowedIPs": ["not an ip"]}`},
+		{name: "maintenance", args: `{"retryAfter": "soon"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.args, func(t *testing.T) {
+			var args json.RawMessage
+			if tt.args != "" {
+				args = json.RawMessage(tt.args)
+			}
+
+			if _, err := catalog.Middleware(tt.name, args, router); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCatalog_Maintenance(t *testing.T) {
+	middleware, err := DefaultCatalog().Middleware("maintenance", json.RawMessage(`{"enabled": true, "message": "Back soon", "retryAfter": "10m", "paths": ["/health"]}`), ki.NewMux())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path       string
+		code       int
+		retryAfter string
+	}{
+		{path: "/", code: http.StatusServiceUnavailable, retryAfter: "600"},
+		{path: "/health", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.code {
+			t.Fatalf("Unexpected status for %s: got=%d, want=%d", tt.path, rec.Code, tt.code)
+		}
+		if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+			t.Fatalf("Unexpected Retry-After for %s: got=%q, want=%q", tt.path, got, tt.retryAfter)
+		}
+	}
+}
+
+func TestCatalog_Assemble(t *testing.T) {
+	config, err := ki.LoadConfig(strings.NewReader(`{
+		"use": [{"use": "no_cache"}],
+		"routes": [{
+			"method": "POST",
+			"pattern": "/posts",
+			"handler": "create-post",
+			"use": [{"use": "content_type", "args": {"types": ["application/json"]}}]
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	handlers := map[string]http.Handler{
+		"create-post": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}),
+	}
+
+	router := ki.NewMux()
+
+	if err := ki.Assemble(router, config, handlers, DefaultCatalog()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status %d, got %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+	if rec.Header().Get("Pragma") != "no-cache" {
+		t.Errorf("expected no-cache headers")
+	}
+}
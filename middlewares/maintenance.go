@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaintenanceOptions configures a Maintenance.
+type MaintenanceOptions struct {
+	// AllowedIPs are the IPs or CIDR networks of the clients served during the maintenance.
+	// They are matched against the remote address, see RealIP.
+	AllowedIPs []string
+	// BypassCookie is the name of the cookie serving the request during the maintenance when its value is BypassToken.
+	BypassCookie string
+	// BypassToken is the value of the bypass cookie, the cookie is ignored if empty.
+	BypassToken string
+	// Paths are the paths served during the maintenance, e.g. the health checks.
+	Paths []string
+	// Render writes the 503 Service Unavailable response, status included.
+	// By default, the message is rendered as JSON or HTML depending on the Accept header.
+	Render func(w http.ResponseWriter, r *http.Request, message string)
+}
+
+// MaintenanceStatus is the status of a Maintenance.
+type MaintenanceStatus struct {
+	Enabled    bool     `json:"enabled"`
+	Message    string   `json:"message,omitempty"`
+	RetryAfter Duration `json:"retryAfter,omitempty"`
+}
+
+// Maintenance is a maintenance mode switch.
+// Use one Maintenance per router or sub-router that can be put into maintenance independently.
+type Maintenance struct {
+	options  MaintenanceOptions
+	networks []*net.IPNet
+
+	mu     sync.RWMutex
+	status MaintenanceStatus
+}
+
+// NewMaintenance returns a new disabled Maintenance.
+// It panics if an allowed IP is invalid.
+func NewMaintenance(options MaintenanceOptions) *Maintenance {
+	m := &Maintenance{
+		options: options,
+	}
+
+	networks, err := parseNetworks(options.AllowedIPs)
+	if err != nil {
+		panic(fmt.Sprintf("invalid allowed IPs (%v)", err))
+	}
+
+	m.networks = networks
+
+	if m.options.Render == nil {
+		m.options.Render = renderMaintenance
+	}
+
+	return m
+}
+
+// Enable enables the maintenance mode with the given message and Retry-After delay.
+func (m *Maintenance) Enable(message string, retryAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = MaintenanceStatus{
+		Enabled:    true,
+		Message:    message,
+		RetryAfter: Duration(retryAfter),
+	}
+}
+
+// Disable disables the maintenance mode.
+func (m *Maintenance) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = MaintenanceStatus{}
+}
+
+// Status returns the status of the maintenance mode.
+func (m *Maintenance) Status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.status
+}
+
+// Middleware returns a middleware that responds 503 Service Unavailable while the maintenance mode is enabled.
+func (m *Maintenance) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := m.Status()
+
+			if !status.Enabled || m.allowed(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if status.RetryAfter > 0 {
+				seconds := int(time.Duration(status.RetryAfter).Round(time.Second).Seconds())
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			}
+
+			w.Header().Add("Vary", "Accept")
+			w.Header().Set("Cache-Control", "no-store")
+
+			m.options.Render(w, r, status.Message)
+		})
+	}
+}
+
+// AdminHandler returns a handler to toggle the maintenance mode:
+//
+//	GET    returns the MaintenanceStatus
+//	PUT    enables the maintenance mode, e.g. {"message": "Back soon", "retryAfter": "10m"}
+//	DELETE disables the maintenance mode
+func (m *Maintenance) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var status MaintenanceStatus
+
+			if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+				http.Error(w, "invalid maintenance status", http.StatusBadRequest)
+				return
+			}
+
+			m.Enable(status.Message, time.Duration(status.RetryAfter))
+		case http.MethodDelete:
+			m.Disable()
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(m.Status())
+	})
+}
+
+// allowed returns true if the request is served during the maintenance.
+func (m *Maintenance) allowed(r *http.Request) bool {
+	if slices.Contains(m.options.Paths, r.URL.Path) {
+		return true
+	}
+
+	if m.options.BypassCookie != "" && m.options.BypassToken != "" {
+		cookie, err := r.Cookie(m.options.BypassCookie)
+		if err == nil && subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(m.options.BypassToken)) == 1 {
+			return true
+		}
+	}
+
+	return containsRemoteAddr(m.networks, r.RemoteAddr)
+}
+
+// renderMaintenance writes the message as JSON if the client accepts JSON or as HTML otherwise.
+func renderMaintenance(w http.ResponseWriter, r *http.Request, message string) {
+	if message == "" {
+		message = "The service is under maintenance."
+	}
+
+	accept := r.Header.Get("Accept")
+
+	if strings.Contains(accept, "application/json") || strings.Contains(accept, "+json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":   "maintenance",
+			"message": message,
+		})
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	_, _ = fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>Maintenance</title></head><body><h1>Maintenance</h1><p>%s</p></body></html>", html.EscapeString(message))
+}
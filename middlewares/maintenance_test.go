@@ -0,0 +1,160 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenance(t *testing.T) {
+	maintenance := NewMaintenance(MaintenanceOptions{
+		AllowedIPs:   []string{"10.0.0.0/8", "192.0.2.10"},
+		BypassCookie: "maintenance_bypass",
+		BypassToken:  "secret",
+		Paths:        []string{"/healthz"},
+	})
+
+	handler := maintenance.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	request := func(path, remoteAddr, accept string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Accept", accept)
+
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := request("/", "203.0.113.1:1234", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 while disabled, got %d", rec.Code)
+	}
+
+	maintenance.Enable("Back in 10 minutes", 10*time.Minute)
+
+	tests := []struct {
+		name        string
+		path        string
+		remoteAddr  string
+		accept      string
+		cookie      *http.Cookie
+		code        int
+		contentType string
+		body        string
+	}{
+		{name: "HTML", path: "/", remoteAddr: "203.0.113.1:1234", accept: "text/html", code: http.StatusServiceUnavailable, contentType: "text/html; charset=utf-8", body: "<p>Back in 10 minutes</p>"},
+		{name: "JSON", path: "/", remoteAddr: "203.0.113.1:1234", accept: "application/json", code: http.StatusServiceUnavailable, contentType: "application/json", body: `"message":"Back in 10 minutes"`},
+		{name: "Allowed network", path: "/", remoteAddr: "10.1.2.3:1234", code: http.StatusOK},
+		{name: "Allowed IP after RealIP", path: "/", remoteAddr: "192.0.2.10", code: http.StatusOK},
+		{name: "Bypass cookie", path: "/", remoteAddr: "203.0.113.1:1234", cookie: &http.Cookie{Name: "maintenance_bypass", Value: "secret"}, code: http.StatusOK},
+		{name: "Wrong bypass cookie", path: "/", remoteAddr: "203.0.113.1:1234", cookie: &http.Cookie{Name: "maintenance_bypass", Value: "guess"}, code: http.StatusServiceUnavailable, contentType: "text/html; charset=utf-8", body: "Maintenance"},
+		{name: "Health check", path: "/healthz", remoteAddr: "203.0.113.1:1234", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := request(tt.path, tt.remoteAddr, tt.accept, tt.cookie)
+
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, rec.Code)
+			}
+
+			if tt.code != http.StatusServiceUnavailable {
+				return
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != "600" {
+				t.Errorf("expected Retry-After 600, got %q", got)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, got)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("expected body to contain %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+
+	maintenance.Disable()
+
+	if rec := request("/", "203.0.113.1:1234", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 after disabling, got %d", rec.Code)
+	}
+}
+
+func TestMaintenance_Render(t *testing.T) {
+	maintenance := NewMaintenance(MaintenanceOptions{
+		Render: func(w http.ResponseWriter, r *http.Request, message string) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("custom: " + message))
+		},
+	})
+	maintenance.Enable("migrating", 0)
+
+	handler := maintenance.Middleware()(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rec.Code)
+	}
+	if rec.Body.String() != "custom: migrating" {
+		t.Fatalf("expected custom body, got %q", rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "" {
+		t.Fatalf("expected no Retry-After, got %q", got)
+	}
+}
+
+func TestMaintenance_AdminHandler(t *testing.T) {
+	maintenance := NewMaintenance(MaintenanceOptions{})
+	admin := maintenance.AdminHandler()
+
+	tests := []struct {
+		method string
+		body   string
+		code   int
+		status string
+	}{
+		{method: http.MethodGet, code: http.StatusOK, status: `{"enabled":false}`},
+		{method: http.MethodPut, body: `{"message":"Back soon","retryAfter":"5m"}`, code: http.StatusOK, status: `{"enabled":true,"message":"Back soon","retryAfter":"5m0s"}`},
+		{method: http.MethodGet, code: http.StatusOK, status: `{"enabled":true,"message":"Back soon","retryAfter":"5m0s"}`},
+		{method: http.MethodPut, body: `{"retryAfter":"soon"}`, code: http.StatusBadRequest},
+		{method: http.MethodDelete, code: http.StatusOK, status: `{"enabled":false}`},
+		{method: http.MethodPost, code: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+
+		admin.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Fatalf("%s: expected status %d, got %d", tt.method, tt.code, rec.Code)
+		}
+		if tt.status != "" && strings.TrimSpace(rec.Body.String()) != tt.status {
+			t.Fatalf("%s: expected status %s, got %s", tt.method, tt.status, rec.Body.String())
+		}
+	}
+}
+
+func TestNewMaintenance_InvalidIP(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for invalid allowed IP")
+		}
+	}()
+
+	NewMaintenance(MaintenanceOptions{AllowedIPs: []string{"not-an-ip"}})
+}
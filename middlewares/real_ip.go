@@ -1,8 +1,10 @@
 package middlewares
 
 import (
+	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 )
 
@@ -36,3 +38,46 @@ func getRealIP(r *http.Request) string {
 
 	return r.RemoteAddr
 }
+
+// parseNetworks returns the networks of the given IPs or CIDR networks.
+func parseNetworks(ips []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+
+	for _, ip := range ips {
+		cidr := ip
+
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP %s", ip)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// containsRemoteAddr returns true if the IP of the remote address belongs to one of the networks.
+func containsRemoteAddr(networks []*net.IPNet, remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(networks, func(network *net.IPNet) bool {
+		return network.Contains(ip)
+	})
+}
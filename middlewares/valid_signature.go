@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/throskam/ki"
+)
+
+// ValidSignature returns a middleware that responds 403 Forbidden unless the request URL is signed by one of the keys
+// and not expired. See ki.Location.Sign.
+// The original request URI is verified, so the middleware can be used inside a sub-router stripping its prefix.
+func ValidSignature(keys ...[]byte) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u := r.URL
+
+			if r.RequestURI != "" {
+				if parsed, err := url.ParseRequestURI(r.RequestURI); err == nil {
+					u = parsed
+				}
+			}
+
+			if err := ki.VerifySignature(u, keys...); err != nil {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/throskam/ki"
+)
+
+func TestValidSignatureMiddleware(t *testing.T) {
+	key := []byte("secret")
+
+	router := ki.NewRouter()
+	router.Route("/downloads", func(r ki.Router) {
+		r.Use(ValidSignature([]byte("new"), key))
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.PathValue("id")))
+		})
+	})
+
+	location := ki.NewLocation(http.MethodGet, "/downloads/{id}").WithPathParams("42")
+
+	tests := []struct {
+		name     string
+		target   string
+		expected int
+	}{
+		{name: "Valid", target: location.Sign(key, time.Now().Add(time.Hour)).String(), expected: http.StatusOK},
+		{name: "Expired", target: location.Sign(key, time.Now().Add(-time.Hour)).String(), expected: http.StatusForbidden},
+		{name: "Wrong key", target: location.Sign([]byte("other"), time.Now().Add(time.Hour)).String(), expected: http.StatusForbidden},
+		{name: "Unsigned", target: "/downloads/42", expected: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, tt.expected)
+			}
+		})
+	}
+}
@@ -4,45 +4,139 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 )
 
-// Mux is a router that uses a ServeMux.
+// Mux is a router that uses a ServeMux by default.
 type Mux struct {
-	mux *http.ServeMux
+	mux matcher
+
+	variants map[string]*routeVariants
+
+	flat bool
+
+	prefix string
+
+	parent *Mux
 
 	registry *Registry
 
+	container *Container
+
 	routeOptions []RouteOption
+
+	namePrefix string
+
+	normalization *PathNormalization
+
+	versioning *versioning
+
+	hooks *hooks
+}
+
+// MuxOption is a function that configures a Mux.
+type MuxOption func(*Mux)
+
+// RouterOption is a function that configures a router created by Route or Group, or mounted by Mount.
+type RouterOption func(*routerConfig)
+
+// routerConfig is the configuration of a router created by Route or Group, or mounted by Mount.
+type routerConfig struct {
+	namePrefix string
+}
+
+// WithNamePrefix returns a new RouterOption that prefixes the names of the routes of the router, e.g. "admin." for
+// "admin.dashboard". The prefixes of the nested routers are concatenated.
+func WithNamePrefix(prefix string) RouterOption {
+	return func(c *routerConfig) {
+		c.namePrefix += prefix
+	}
+}
+
+// newRouterConfig returns the configuration for the given options.
+func newRouterConfig(options []RouterOption) routerConfig {
+	config := routerConfig{}
+
+	for _, o := range options {
+		o(&config)
+	}
+
+	return config
 }
 
 // NewMux returns a new Mux.
-func NewMux() *Mux {
-	return &Mux{
+func NewMux(options ...MuxOption) *Mux {
+	mux := &Mux{
 		mux:          http.NewServeMux(),
+		variants:     map[string]*routeVariants{},
 		registry:     NewRegistry(),
+		container:    NewContainer(),
 		routeOptions: []RouteOption{},
+		hooks:        &hooks{},
+	}
+
+	for _, o := range options {
+		o(mux)
 	}
+
+	return mux
 }
 
 // ServeHTTP implements the http.Handler interface.
 func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	m.mux.ServeHTTP(w, r)
+	if m.parent != nil {
+		m.mux.ServeHTTP(w, r)
+		return
+	}
+
+	var handler http.Handler = m.mux
+
+	if m.versioning != nil {
+		handler = m.versioning.Handler(m.mux)
+	}
+
+	if m.normalization != nil {
+		handler = m.normalization.Handler(handler)
+	}
+
+	m.hooks.serve(w, r, handler)
 }
 
 // Mount mounts the given handler at the given prefix.
-func (m *Mux) Mount(prefix string, handler http.Handler) {
+// The registry of a mounted Router is attached to the registry of the router, so that its named routes can be
+// resolved with the prefix and the name prefix of the router.
+func (m *Mux) Mount(prefix string, handler http.Handler, options ...RouterOption) {
+	config := newRouterConfig(options)
+
 	m.mount(prefix, handler)
-}
 
-// Route creates a new router with the given prefix.
-func (m *Mux) Route(prefix string, fn func(Router)) Router {
-	mux := &Mux{
-		mux:          http.NewServeMux(),
-		registry:     m.registry.Child(prefix),
-		routeOptions: []RouteOption{},
+	root := m.root()
+
+	root.hooks.registered(RouteInfo{
+		Path:  m.prefix + prefix + "/",
+		Mount: true,
+	})
+
+	router, ok := handler.(Router)
+	if !ok {
+		return
 	}
 
-	m.Mount(prefix, mux)
+	m.registry.attach(prefix, m.namePrefix+config.namePrefix, routeOptionsMiddlewares(m.routeOptions), router.Registry())
+
+	for _, route := range router.Registry().Routes() {
+		root.hooks.registered(RouteInfo{
+			Name:   route.Name,
+			Method: route.Method,
+			Path:   route.Pattern,
+		})
+	}
+}
+
+// Route creates a new router with the given prefix.
+func (m *Mux) Route(prefix string, fn func(Router), options ...RouterOption) Router {
+	mux := m.route(prefix)
+	mux.setNamePrefix(mux.namePrefix + newRouterConfig(options).namePrefix)
 
 	if fn != nil {
 		fn(mux)
@@ -52,11 +146,17 @@ func (m *Mux) Route(prefix string, fn func(Router)) Router {
 }
 
 // Group creates a new router without any prefix.
-func (m *Mux) Group(fn func(Router)) Router {
+func (m *Mux) Group(fn func(Router), options ...RouterOption) Router {
 	mux := &Mux{
 		mux:          m.mux,
+		variants:     m.variants,
+		flat:         m.flat,
+		prefix:       m.prefix,
+		parent:       m,
 		registry:     m.registry,
+		container:    m.container.Child(),
 		routeOptions: slices.Clone(m.routeOptions),
+		namePrefix:   m.namePrefix + newRouterConfig(options).namePrefix,
 	}
 
 	if fn != nil {
@@ -106,31 +206,158 @@ func (m *Mux) Registry() *Registry {
 	return m.registry
 }
 
+// Container returns the service container of the router.
+func (m *Mux) Container() *Container {
+	return m.container
+}
+
 // handle adds the route to the mux.
+// The routes sharing a pattern are added as variants of the pattern.
 func (m *Mux) handle(route Route) {
-	m.mux.Handle(route.Pattern(), route.Handler())
+	pattern := m.pattern(route)
+
+	variants, ok := m.variants[pattern]
+	if !ok {
+		variants = &routeVariants{pattern: pattern, siblings: m.variants}
+
+		m.mux.Handle(pattern, variants)
+		m.variants[pattern] = variants
+	}
+
+	variants.add(route.matchers, inheritPathValues(withContainer(m.container, route.Handler())))
+}
+
+// pattern returns the pattern of the route as registered in the mux.
+// When the routes are flattened, the path is prefixed by the prefix of the router.
+func (m *Mux) pattern(route Route) string {
+	if !m.flat || m.prefix == "" {
+		return route.Pattern()
+	}
+
+	path := m.prefix + route.Path()
+
+	if route.Path() == "/{$}" && m.root().stripsTrailingSlash() {
+		// The trailing slash never reaches the mux, so "/{$}" is routed at the prefix itself.
+		path = m.prefix
+	}
+
+	if route.Method() == "" {
+		return path
+	}
+
+	return fmt.Sprintf("%s %s", route.Method(), path)
+}
+
+// route creates a new sub-router with the given prefix.
+// When the routes are flattened, the sub-router shares the mux of the router instead of being mounted.
+func (m *Mux) route(prefix string) *Mux {
+	if m.flat {
+		mux := &Mux{
+			mux:          m.mux,
+			variants:     m.variants,
+			flat:         true,
+			prefix:       m.prefix + prefix,
+			parent:       m,
+			registry:     m.registry.Child(prefix),
+			container:    m.container.Child(),
+			routeOptions: slices.Clone(m.routeOptions),
+		}
+
+		mux.setNamePrefix(m.namePrefix)
+
+		return mux
+	}
+
+	mux := &Mux{
+		mux:      http.NewServeMux(),
+		variants: map[string]*routeVariants{},
+		prefix:   m.prefix + prefix,
+		parent:   m,
+		// The middlewares of the router wrap the mounted sub-router.
+		registry:     m.registry.child(prefix, routeOptionsMiddlewares(m.routeOptions)),
+		container:    m.container.Child(),
+		routeOptions: []RouteOption{},
+	}
+
+	mux.setNamePrefix(m.namePrefix)
+
+	m.mount(prefix, mux)
+
+	return mux
 }
 
 // mount mounts the given handler at the given prefix.
 func (m *Mux) mount(prefix string, handler http.Handler) {
 	pattern := fmt.Sprintf("%s/", prefix)
 
+	// The flattened routes are matched against the full path.
+	strip := prefix
+	if m.flat {
+		strip = m.prefix + prefix
+	}
+
 	route := NewRoute("", pattern, handler, slices.Concat(m.routeOptions, []RouteOption{WithMiddleware(
-		func(next http.Handler) http.Handler { return http.StripPrefix(prefix, next) },
+		stripPrefix(strip),
 	)})...)
 
 	m.handle(route)
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") && m.root().stripsTrailingSlash() {
+		// The trailing slash never reaches the mux, so the prefix itself is routed to "/".
+		route := NewRoute("", prefix, handler, slices.Concat(m.routeOptions, []RouteOption{WithMiddleware(
+			func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, withPath(withPrefixPathValues(r, strip), "/"))
+				})
+			},
+		)})...)
+
+		m.handle(route)
+	}
+}
+
+// setNamePrefix sets the name prefix of the router and the namespace of its registry.
+func (m *Mux) setNamePrefix(prefix string) {
+	m.namePrefix = prefix
+	m.registry.setNamespace(prefix)
+}
+
+// root returns the top-level router.
+func (m *Mux) root() *Mux {
+	root := m
+
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+// stripsTrailingSlash returns true if the router strips the trailing slashes before matching.
+func (m *Mux) stripsTrailingSlash() bool {
+	return m.normalization != nil && m.normalization.TrailingSlash == TrailingSlashStrip
 }
 
 // method adds a route for the given verb.
 func (m *Mux) method(method, pattern string, handler http.HandlerFunc, options ...RouteOption) Location {
 	route := NewRoute(method, pattern, handler, slices.Concat(m.routeOptions, options)...)
 
+	name := ""
 	if route.Name() != "" {
-		m.registry.Add(route.Name(), route.Method(), route.Path())
+		name = m.namePrefix + route.Name()
+	}
+
+	m.root().hooks.registered(RouteInfo{
+		Name:   name,
+		Method: route.Method(),
+		Path:   m.prefix + route.Path(),
+	})
+
+	if name != "" {
+		m.registry.add(name, route.Location(), middlewareNames(route.middlewares))
 	}
 
 	m.handle(route)
 
 	return route.Location()
-}
\ No newline at end of file
+}
@@ -3,6 +3,7 @@ package ki
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -305,3 +306,147 @@ func TestMux_404(t *testing.T) {
 		t.Fatalf("Expected 404, got %d", rec.Code)
 	}
 }
+
+func testOuterMiddleware(next http.Handler) http.Handler {
+	return next
+}
+
+func testInnerMiddleware(next http.Handler) http.Handler {
+	return next
+}
+
+func TestMux_MountRouter(t *testing.T) {
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			registered := []RouteInfo{}
+
+			mux := NewMux(option)
+			mux.OnRouteRegistered(func(info RouteInfo) {
+				registered = append(registered, info)
+			})
+			mux.Use(testOuterMiddleware)
+
+			admin := NewRouter()
+			admin.Get("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("dashboard"))
+			}, WithName("admin-dashboard"), WithMiddleware(testInnerMiddleware))
+
+			mux.Route("/api", func(r Router) {
+				r.Mount("/admin", admin)
+			})
+
+			location := mux.Registry().Get("admin-dashboard")
+			if got := location.URL().String(); got != "/api/admin/dashboard" {
+				t.Fatalf("Incorrect path: got=%s, want=%s", got, "/api/admin/dashboard")
+			}
+
+			req := httptest.NewRequest(http.MethodGet, location.URL().String(), nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK || rec.Body.String() != "dashboard" {
+				t.Fatalf("Unexpected response: got=%d %q", rec.Code, rec.Body.String())
+			}
+
+			routes := mux.Registry().Routes()
+			if len(routes) != 1 {
+				t.Fatalf("Unexpected routes: got=%+v", routes)
+			}
+
+			expected := []string{"github.com/throskam/ki.testOuterMiddleware", "github.com/throskam/ki.testInnerMiddleware"}
+			if !slices.Equal(routes[0].Middlewares, expected) {
+				t.Fatalf("Unexpected middlewares: got=%v, want=%v", routes[0].Middlewares, expected)
+			}
+			if routes[0].Pattern != "/api/admin/dashboard" {
+				t.Fatalf("Unexpected pattern: got=%s", routes[0].Pattern)
+			}
+
+			last := registered[len(registered)-1]
+			if last.Name != "admin-dashboard" || last.Path != "/api/admin/dashboard" {
+				t.Fatalf("Unexpected hook: got=%+v", last)
+			}
+		})
+	}
+}
+
+func TestMux_NamePrefix(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux(option)
+
+			var users Router
+
+			mux.Route("/admin", func(r Router) {
+				r.Get("/dashboard", handler, WithName("dashboard"))
+
+				users = r.Route("/users", func(r Router) {
+					r.Get("/{id}", handler, WithName("show"))
+				}, WithNamePrefix("users."))
+
+				r.Group(func(r Router) {
+					r.Get("/reports", handler, WithName("index"))
+				}, WithNamePrefix("reports."))
+			}, WithNamePrefix("admin."))
+
+			billing := NewRouter()
+			billing.Get("/invoices", handler, WithName("invoices"))
+
+			mux.Mount("/billing", billing, WithNamePrefix("billing."))
+
+			expected := map[string]string{
+				"admin.dashboard":     "/admin/dashboard",
+				"admin.users.show":    "/admin/users/{id}",
+				"admin.reports.index": "/admin/reports",
+				"billing.invoices":    "/billing/invoices",
+			}
+
+			for name, path := range expected {
+				if got := mux.Registry().Get(name).URL().Path; got != path {
+					t.Fatalf("Incorrect path for %s: got=%s, want=%s", name, got, path)
+				}
+			}
+
+			relative := map[string]string{
+				"show":             "/admin/users/{id}",
+				"admin.dashboard":  "/admin/dashboard",
+				"billing.invoices": "/billing/invoices",
+			}
+
+			for name, path := range relative {
+				if got := users.Registry().Get(name).URL().Path; got != path {
+					t.Fatalf("Incorrect relative path for %s: got=%s, want=%s", name, got, path)
+				}
+			}
+
+			if got := billing.Registry().Get("invoices").URL().String(); got != "/billing/invoices" {
+				t.Fatalf("Incorrect path from mounted router: got=%s", got)
+			}
+
+			if mux.Registry().Has("dashboard") {
+				t.Fatal("expected unqualified name to be unknown from the root")
+			}
+
+			tree := mux.Registry().Tree()
+
+			names := []string{}
+			for _, namespace := range tree.Namespaces {
+				names = append(names, namespace.Name)
+			}
+
+			if !slices.Equal(names, []string{"admin", "billing"}) {
+				t.Fatalf("Unexpected namespaces: got=%v", names)
+			}
+
+			admin := tree.Namespaces[0]
+			if len(admin.Routes) != 1 || admin.Routes[0].Name != "admin.dashboard" {
+				t.Fatalf("Unexpected admin routes: got=%+v", admin.Routes)
+			}
+			if len(admin.Namespaces) != 2 || admin.Namespaces[0].Name != "admin.reports" || admin.Namespaces[1].Name != "admin.users" {
+				t.Fatalf("Unexpected admin namespaces: got=%+v", admin.Namespaces)
+			}
+		})
+	}
+}
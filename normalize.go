@@ -0,0 +1,178 @@
+package ki
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NormalizationMode defines how a request with a non-canonical path is handled.
+type NormalizationMode int
+
+const (
+	// NormalizeRewrite rewrites the request path before matching.
+	NormalizeRewrite NormalizationMode = iota
+	// NormalizeRedirect redirects the client to the canonical path.
+	NormalizeRedirect
+)
+
+// TrailingSlash defines how trailing slashes are handled.
+type TrailingSlash int
+
+const (
+	// TrailingSlashKeep leaves trailing slashes untouched.
+	TrailingSlashKeep TrailingSlash = iota
+	// TrailingSlashStrip removes the trailing slash.
+	TrailingSlashStrip
+	// TrailingSlashAdd appends a trailing slash.
+	TrailingSlashAdd
+)
+
+// PathNormalization is a path normalization policy.
+type PathNormalization struct {
+	// Mode defines whether the path is rewritten or redirected.
+	Mode NormalizationMode
+	// TrailingSlash defines how trailing slashes are handled.
+	TrailingSlash TrailingSlash
+	// CollapseSlashes collapses duplicate slashes.
+	CollapseSlashes bool
+	// ResolveDots resolves the "." and ".." segments.
+	ResolveDots bool
+	// LowerCase lower-cases the path.
+	LowerCase bool
+}
+
+// WithPathNormalization returns a new MuxOption that normalizes the request path before matching.
+// When the trailing slashes are stripped, the prefix of a sub-router is served by its "/{$}" route.
+func WithPathNormalization(policy PathNormalization) MuxOption {
+	return func(m *Mux) {
+		m.normalization = &policy
+	}
+}
+
+// Normalize returns the canonical form of the given path.
+func (p PathNormalization) Normalize(path string) string {
+	if path == "" {
+		path = "/"
+	}
+
+	if p.CollapseSlashes {
+		path = collapseSlashes(path)
+	}
+
+	if p.ResolveDots {
+		path = resolveDots(path)
+	}
+
+	if p.LowerCase {
+		path = strings.ToLower(path)
+	}
+
+	switch p.TrailingSlash {
+	case TrailingSlashStrip:
+		// The path is stripped down to the root at most, e.g. "//" is normalized to "/".
+		path = strings.TrimRight(path, "/")
+		if path == "" {
+			path = "/"
+		}
+	case TrailingSlashAdd:
+		if !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+	}
+
+	return path
+}
+
+// Handler returns a handler that applies the policy before calling the given handler.
+func (p PathNormalization) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := p.Normalize(r.URL.Path)
+
+		if path == r.URL.Path {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if p.Mode == NormalizeRedirect {
+			// A target starting with "//" or "/\" would redirect to another host.
+			path = "/" + strings.TrimLeft(path, "/\\")
+
+			target := (&url.URL{Path: path, RawQuery: r.URL.RawQuery}).String()
+
+			code := http.StatusPermanentRedirect
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
+			}
+
+			// http.Redirect would clean the target, which is up to the policy.
+			w.Header().Set("Location", target)
+			w.WriteHeader(code)
+			return
+		}
+
+		next.ServeHTTP(w, withPath(r, path))
+	})
+}
+
+// withPath returns a shallow copy of the request with the given path.
+func withPath(r *http.Request, path string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+
+	return r2
+}
+
+// collapseSlashes replaces the sequences of slashes by a single slash.
+func collapseSlashes(path string) string {
+	var b strings.Builder
+
+	b.Grow(len(path))
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
+
+// resolveDots resolves the "." and ".." segments.
+func resolveDots(path string) string {
+	segments := strings.Split(path, "/")
+	resolved := make([]string, 0, len(segments))
+
+	for i, segment := range segments {
+		last := i == len(segments)-1
+
+		switch segment {
+		case ".":
+			if last {
+				resolved = append(resolved, "")
+			}
+		case "..":
+			if len(resolved) > 1 {
+				resolved = resolved[:len(resolved)-1]
+			}
+
+			if last {
+				resolved = append(resolved, "")
+			}
+		default:
+			resolved = append(resolved, segment)
+		}
+	}
+
+	if len(resolved) == 1 {
+		return "/"
+	}
+
+	return strings.Join(resolved, "/")
+}
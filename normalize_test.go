@@ -0,0 +1,227 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathNormalization_Normalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   PathNormalization
+		path     string
+		expected string
+	}{
+		{
+			name:     "No policy",
+			policy:   PathNormalization{},
+			path:     "//Foo/./bar/",
+			expected: "//Foo/./bar/",
+		},
+		{
+			name:     "Collapse slashes",
+			policy:   PathNormalization{CollapseSlashes: true},
+			path:     "//foo///bar",
+			expected: "/foo/bar",
+		},
+		{
+			name:     "Resolve dots",
+			policy:   PathNormalization{ResolveDots: true},
+			path:     "/foo/./bar/../baz",
+			expected: "/foo/baz",
+		},
+		{
+			name:     "Resolve dots above root",
+			policy:   PathNormalization{ResolveDots: true},
+			path:     "/../../foo",
+			expected: "/foo",
+		},
+		{
+			name:     "Resolve trailing dots",
+			policy:   PathNormalization{ResolveDots: true},
+			path:     "/foo/bar/..",
+			expected: "/foo/",
+		},
+		{
+			name:     "Lower case",
+			policy:   PathNormalization{LowerCase: true},
+			path:     "/Foo/BAR",
+			expected: "/foo/bar",
+		},
+		{
+			name:     "Strip trailing slash",
+			policy:   PathNormalization{TrailingSlash: TrailingSlashStrip},
+			path:     "/foo/",
+			expected: "/foo",
+		},
+		{
+			name:     "Strip trailing slash keeps root",
+			policy:   PathNormalization{TrailingSlash: TrailingSlashStrip},
+			path:     "/",
+			expected: "/",
+		},
+		{
+			name:     "Strip trailing slashes keeps root",
+			policy:   PathNormalization{TrailingSlash: TrailingSlashStrip},
+			path:     "//",
+			expected: "/",
+		},
+		{
+			name:     "Add trailing slash",
+			policy:   PathNormalization{TrailingSlash: TrailingSlashAdd},
+			path:     "/foo",
+			expected: "/foo/",
+		},
+		{
+			name: "Everything",
+			policy: PathNormalization{
+				TrailingSlash:   TrailingSlashStrip,
+				CollapseSlashes: true,
+				ResolveDots:     true,
+				LowerCase:       true,
+			},
+			path:     "//Foo/./Bar//../Baz/",
+			expected: "/foo/baz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Normalize(tt.path); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPathNormalization_Redirect(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	mux := NewMux(WithPathNormalization(PathNormalization{
+		Mode:          NormalizeRedirect,
+		TrailingSlash: TrailingSlashStrip,
+		LowerCase:     true,
+	}))
+	mux.Get("/foo", handler)
+	mux.Post("/foo", handler)
+
+	tests := []struct {
+		method   string
+		code     int
+		location string
+	}{
+		{method: http.MethodGet, code: http.StatusMovedPermanently, location: "/foo?q=Bar"},
+		{method: http.MethodPost, code: http.StatusPermanentRedirect, location: "/foo?q=Bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/Foo/?q=Bar", nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.location {
+				t.Fatalf("expected location %q, got %q", tt.location, got)
+			}
+		})
+	}
+}
+
+func TestPathNormalization_Rewrite(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+	})
+
+	mux := NewMux(WithPathNormalization(PathNormalization{
+		Mode:            NormalizeRewrite,
+		TrailingSlash:   TrailingSlashStrip,
+		CollapseSlashes: true,
+		ResolveDots:     true,
+	}))
+	mux.Get("/foo/bar", handler)
+	mux.Route("/posts", func(r Router) {
+		r.Get("/{$}", handler)
+		r.Get("/{id}", handler)
+	})
+
+	tests := map[string]string{
+		"//foo/./bar/?q=1":  "/foo/bar?q=1",
+		"/foo/baz/../bar":   "/foo/bar?",
+		"/posts":            "/?",
+		"/posts/":           "/?",
+		"/posts/1/?page=2":  "/1?page=2",
+		"/posts//1/../2/":   "/2?",
+		"/posts/./?sort=up": "/?sort=up",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Unexpected status for %s: got=%d", path, rec.Code)
+			}
+			if rec.Body.String() != expected {
+				t.Fatalf("Unexpected body for %s: got=%q, want=%q", path, rec.Body.String(), expected)
+			}
+		})
+	}
+}
+
+func TestPathNormalization_RedirectStaysOnHost(t *testing.T) {
+	mux := NewMux(WithPathNormalization(PathNormalization{
+		Mode:          NormalizeRedirect,
+		TrailingSlash: TrailingSlashStrip,
+	}))
+
+	tests := map[string]string{
+		"//evil.com/":    "/evil.com",
+		"/%2Fevil.com/":  "/evil.com",
+		"/%5C/evil.com/": "/evil.com",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusMovedPermanently {
+				t.Fatalf("Unexpected status for %s: got=%d, want=%d", path, rec.Code, http.StatusMovedPermanently)
+			}
+			if got := rec.Header().Get("Location"); got != expected {
+				t.Fatalf("Unexpected location for %s: got=%q, want=%q", path, got, expected)
+			}
+		})
+	}
+}
+
+func TestPathNormalization_RedirectKeepsPath(t *testing.T) {
+	mux := NewMux(WithPathNormalization(PathNormalization{
+		Mode:          NormalizeRedirect,
+		TrailingSlash: TrailingSlashStrip,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/foo//bar/./baz/../?q=1", nil)
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, http.StatusMovedPermanently)
+	}
+	if got := rec.Header().Get("Location"); got != "/foo//bar/./baz/..?q=1" {
+		t.Fatalf("Unexpected location: got=%q, want=%q", got, "/foo//bar/./baz/..?q=1")
+	}
+}
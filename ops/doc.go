@@ -0,0 +1,5 @@
+// Package ops provides long-running operations for the ki router.
+//
+// An operation is started by a handler responding 202 Accepted with a Location header pointing at the status route of
+// the operation, which the client polls until the operation completes.
+package ops
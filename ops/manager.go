@@ -0,0 +1,376 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/throskam/ki"
+)
+
+const (
+	// StatusRouteName is the name of the route returning the status of an operation.
+	StatusRouteName = "operation-status"
+	// CancelRouteName is the name of the route canceling an operation.
+	CancelRouteName = "operation-cancel"
+)
+
+var (
+	// ErrQueueFull is returned when the queue of the manager is full.
+	ErrQueueFull = errors.New("operation queue full")
+	// ErrShutdown is returned when the manager is shut down.
+	ErrShutdown = errors.New("operation manager shut down")
+	// ErrDone is returned when canceling a completed operation.
+	ErrDone = errors.New("operation already done")
+)
+
+// Task is the work of an operation.
+// It should report its progress between 0 and 1 and return when the context is canceled.
+type Task func(ctx context.Context, progress func(float64)) (any, error)
+
+// Options configures a Manager.
+type Options struct {
+	// Workers is the number of operations processed concurrently, 4 by default.
+	Workers int
+	// QueueSize is the number of operations waiting for a worker, 100 by default.
+	QueueSize int
+	// TTL is the retention of the completed operations, 1 hour by default.
+	TTL time.Duration
+	// SweepInterval is the interval between the deletions of the expired operations, 1 minute by default.
+	SweepInterval time.Duration
+}
+
+// Manager runs the operations with a bounded pool of workers.
+type Manager struct {
+	store   Store
+	options Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	closed  bool
+	queue   chan job
+	cancels map[string]context.CancelFunc
+
+	workers sync.WaitGroup
+	sweeper sync.WaitGroup
+	stop    chan struct{}
+}
+
+// job is a queued operation.
+type job struct {
+	id   string
+	task Task
+}
+
+// NewManager returns a new Manager and starts its workers.
+func NewManager(store Store, options Options) *Manager {
+	if options.Workers <= 0 {
+		options.Workers = 4
+	}
+
+	if options.QueueSize <= 0 {
+		options.QueueSize = 100
+	}
+
+	if options.TTL <= 0 {
+		options.TTL = time.Hour
+	}
+
+	if options.SweepInterval <= 0 {
+		options.SweepInterval = time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &Manager{
+		store:   store,
+		options: options,
+		ctx:     ctx,
+		cancel:  cancel,
+		queue:   make(chan job, options.QueueSize),
+		cancels: map[string]context.CancelFunc{},
+		stop:    make(chan struct{}),
+	}
+
+	for range options.Workers {
+		m.workers.Add(1)
+
+		go m.work()
+	}
+
+	m.sweeper.Add(1)
+
+	go m.sweep()
+
+	return m
+}
+
+// Start queues a new operation running the task.
+func (m *Manager) Start(ctx context.Context, task Task) (Operation, error) {
+	now := time.Now()
+
+	op := Operation{
+		ID:        uuid.New().String(),
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return Operation{}, ErrShutdown
+	}
+
+	if len(m.queue) == cap(m.queue) {
+		return Operation{}, ErrQueueFull
+	}
+
+	if err := m.store.Create(ctx, op); err != nil {
+		return Operation{}, err
+	}
+
+	m.queue <- job{id: op.ID, task: task}
+
+	return op, nil
+}
+
+// Accept starts a new operation running the task and responds 202 Accepted with a Location header pointing at the
+// status route of the operation.
+// Use with Locator middleware.
+func (m *Manager) Accept(w http.ResponseWriter, r *http.Request, task Task) {
+	op, err := m.Start(r.Context(), task)
+
+	switch {
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrShutdown):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", ki.GetLocation(r.Context(), StatusRouteName).WithPathParams(op.ID).URL().String())
+
+	writeJSON(w, http.StatusAccepted, op)
+}
+
+// Cancel cancels the operation with the given ID.
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	var done, running bool
+
+	err := m.store.Update(ctx, id, func(op *Operation) {
+		done = op.Done()
+		running = op.Status == StatusRunning
+
+		if op.Status == StatusPending {
+			op.Status = StatusCanceled
+			op.UpdatedAt = time.Now()
+			op.ExpiresAt = op.UpdatedAt.Add(m.options.TTL)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if done {
+		return ErrDone
+	}
+
+	// The cancel function is looked up after the update: a worker registers it before marking the operation running.
+	if running {
+		m.mu.Lock()
+		cancel, ok := m.cancels[id]
+		m.mu.Unlock()
+
+		if ok {
+			cancel()
+		}
+	}
+
+	return nil
+}
+
+// Routes adds the named routes returning the status of an operation and canceling it to the router.
+//
+//	GET    /{id} operation-status
+//	DELETE /{id} operation-cancel
+func (m *Manager) Routes(router ki.Router) {
+	router.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		op, err := m.store.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, op)
+	}, ki.WithName(StatusRouteName))
+
+	router.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		err := m.Cancel(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		op, err := m.store.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusAccepted, op)
+	}, ki.WithName(CancelRouteName))
+}
+
+// Shutdown stops accepting new operations and waits for the queued operations to complete.
+// When the context is done, the running operations are canceled.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+		close(m.stop)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		m.workers.Wait()
+		m.sweeper.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.cancel()
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work processes the queued operations until the queue is closed.
+func (m *Manager) work() {
+	defer m.workers.Done()
+
+	for j := range m.queue {
+		m.run(j)
+	}
+}
+
+// run runs the task of the operation.
+func (m *Manager) run(j job) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	m.mu.Lock()
+	m.cancels[j.id] = cancel
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.cancels, j.id)
+		m.mu.Unlock()
+	}()
+
+	var canceled bool
+
+	err := m.store.Update(ctx, j.id, func(op *Operation) {
+		canceled = op.Status == StatusCanceled
+
+		if !canceled {
+			op.Status = StatusRunning
+			op.UpdatedAt = time.Now()
+		}
+	})
+	if err != nil || canceled {
+		return
+	}
+
+	progress := func(p float64) {
+		_ = m.store.Update(context.Background(), j.id, func(op *Operation) {
+			op.Progress = min(max(p, 0), 1)
+			op.UpdatedAt = time.Now()
+		})
+	}
+
+	result, err := call(ctx, j.task, progress)
+
+	_ = m.store.Update(context.Background(), j.id, func(op *Operation) {
+		switch {
+		case err != nil && ctx.Err() != nil:
+			op.Status = StatusCanceled
+			op.Error = ctx.Err().Error()
+		case err != nil:
+			op.Status = StatusFailed
+			op.Error = err.Error()
+		default:
+			op.Status = StatusSucceeded
+			op.Progress = 1
+			op.Result = result
+		}
+
+		op.UpdatedAt = time.Now()
+		op.ExpiresAt = op.UpdatedAt.Add(m.options.TTL)
+	})
+}
+
+// call calls the task, turning a panic into an error.
+func call(ctx context.Context, task Task, progress func(float64)) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return task(ctx, progress)
+}
+
+// sweep deletes the expired operations periodically until the manager is shut down.
+func (m *Manager) sweep() {
+	defer m.sweeper.Done()
+
+	ticker := time.NewTicker(m.options.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			_ = m.store.DeleteExpired(context.Background(), now)
+		}
+	}
+}
+
+// writeJSON writes the value as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error as a plain text response.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrDone):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,272 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/throskam/ki"
+	"github.com/throskam/ki/middlewares"
+)
+
+func waitFor(t *testing.T, store Store, id string, status Status) Operation {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		op, err := store.Get(context.Background(), id)
+		if err == nil && op.Status == status {
+			return op
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("operation %s never reached status %s", id, status)
+
+	return Operation{}
+}
+
+func TestManager_Accept(t *testing.T) {
+	store := NewMemoryStore()
+	manager := NewManager(store, Options{Workers: 1})
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	router := ki.NewRouter()
+	router.Use(middlewares.Locator(router))
+	router.Route("/operations", manager.Routes)
+	router.Post("/exports", func(w http.ResponseWriter, r *http.Request) {
+		manager.Accept(w, r, func(ctx context.Context, progress func(float64)) (any, error) {
+			progress(0.5)
+			return "export.csv", nil
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/exports", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+	}
+
+	var op Operation
+	if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	location := rec.Header().Get("Location")
+	if location != "/operations/"+op.ID {
+		t.Fatalf("unexpected location %q", location)
+	}
+
+	waitFor(t, store, op.ID, StatusSucceeded)
+
+	req = httptest.NewRequest(http.MethodGet, location, nil)
+	rec = httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if op.Status != StatusSucceeded || op.Progress != 1 || op.Result != "export.csv" || op.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, location, nil)
+	rec = httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/operations/unknown", nil)
+	rec = httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestManager_CancelAndFail(t *testing.T) {
+	store := NewMemoryStore()
+	manager := NewManager(store, Options{Workers: 1})
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	started := make(chan struct{})
+
+	running, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pending, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		return nil, errors.New("never run")
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	failing, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		return nil, errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	<-started
+
+	if err := manager.Cancel(context.Background(), pending.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := manager.Cancel(context.Background(), running.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	waitFor(t, store, running.ID, StatusCanceled)
+	waitFor(t, store, pending.ID, StatusCanceled)
+
+	op := waitFor(t, store, failing.ID, StatusFailed)
+	if op.Error != "boom" {
+		t.Fatalf("expected error %q, got %q", "boom", op.Error)
+	}
+}
+
+func TestManager_QueueFullAndShutdown(t *testing.T) {
+	manager := NewManager(NewMemoryStore(), Options{Workers: 1, QueueSize: 1})
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+
+	task := func(ctx context.Context, progress func(float64)) (any, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+
+		return nil, ctx.Err()
+	}
+
+	if _, err := manager.Start(context.Background(), task); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	<-started
+
+	if _, err := manager.Start(context.Background(), task); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := manager.Start(context.Background(), task); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := manager.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if _, err := manager.Start(context.Background(), task); !errors.Is(err, ErrShutdown) {
+		t.Fatalf("expected ErrShutdown, got %v", err)
+	}
+}
+
+// hookStore is a Store calling a hook once before the next update.
+type hookStore struct {
+	Store
+
+	hook atomic.Pointer[func()]
+}
+
+func (s *hookStore) Update(ctx context.Context, id string, fn func(*Operation)) error {
+	if hook := s.hook.Swap(nil); hook != nil {
+		(*hook)()
+	}
+
+	return s.Store.Update(ctx, id, fn)
+}
+
+func TestManager_CancelWhileStarting(t *testing.T) {
+	store := &hookStore{Store: NewMemoryStore()}
+	manager := NewManager(store, Options{Workers: 1})
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	release := make(chan struct{})
+
+	_, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		<-release
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	started := make(chan struct{})
+
+	queued, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The queued operation starts running while Cancel is about to update it.
+	hook := func() {
+		close(release)
+		<-started
+	}
+	store.hook.Store(&hook)
+
+	if err := manager.Cancel(context.Background(), queued.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	waitFor(t, store, queued.ID, StatusCanceled)
+}
+
+func TestManager_Panic(t *testing.T) {
+	store := NewMemoryStore()
+	manager := NewManager(store, Options{Workers: 1})
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	op, err := manager.Start(context.Background(), func(ctx context.Context, progress func(float64)) (any, error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	op = waitFor(t, store, op.ID, StatusFailed)
+	if op.Error != "panic: boom" {
+		t.Fatalf("expected error %q, got %q", "panic: boom", op.Error)
+	}
+}
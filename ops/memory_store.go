@@ -0,0 +1,84 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	operations map[string]Operation
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		operations: map[string]Operation{},
+	}
+}
+
+// Create stores a new operation.
+func (s *MemoryStore) Create(_ context.Context, op Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.operations[op.ID]; ok {
+		return fmt.Errorf("operation %s already exists", op.ID)
+	}
+
+	s.operations[op.ID] = op
+
+	return nil
+}
+
+// Get returns the operation with the given ID.
+func (s *MemoryStore) Get(_ context.Context, id string) (Operation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	op, ok := s.operations[id]
+	if !ok || expired(op, time.Now()) {
+		return Operation{}, ErrNotFound
+	}
+
+	return op, nil
+}
+
+// Update updates the operation with the given ID.
+func (s *MemoryStore) Update(_ context.Context, id string, fn func(*Operation)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.operations[id]
+	if !ok || expired(op, time.Now()) {
+		return ErrNotFound
+	}
+
+	fn(&op)
+
+	s.operations[id] = op
+
+	return nil
+}
+
+// DeleteExpired deletes the operations expired at the given time.
+func (s *MemoryStore) DeleteExpired(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, op := range s.operations {
+		if expired(op, now) {
+			delete(s.operations, id)
+		}
+	}
+
+	return nil
+}
+
+// expired returns true if the operation is expired at the given time.
+func expired(op Operation, now time.Time) bool {
+	return !op.ExpiresAt.IsZero() && !now.Before(op.ExpiresAt)
+}
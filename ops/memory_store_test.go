@@ -0,0 +1,68 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	err := store.Create(ctx, Operation{ID: "1", Status: StatusPending})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := store.Create(ctx, Operation{ID: "1"}); err == nil {
+		t.Fatal("expected error on duplicate operation")
+	}
+
+	err = store.Update(ctx, "1", func(op *Operation) {
+		op.Status = StatusRunning
+		op.Progress = 0.5
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	op, err := store.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if op.Status != StatusRunning || op.Progress != 0.5 {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+
+	if _, err := store.Get(ctx, "2"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.Update(ctx, "2", func(op *Operation) {}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	now := time.Now()
+
+	_ = store.Create(ctx, Operation{ID: "expired", ExpiresAt: now.Add(-time.Second)})
+	_ = store.Create(ctx, Operation{ID: "alive", ExpiresAt: now.Add(time.Hour)})
+	_ = store.Create(ctx, Operation{ID: "running"})
+
+	if _, err := store.Get(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for expired operation, got %v", err)
+	}
+
+	if err := store.DeleteExpired(ctx, now); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(store.operations) != 2 {
+		t.Fatalf("expected 2 operations after deletion, got %d", len(store.operations))
+	}
+}
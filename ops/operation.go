@@ -0,0 +1,60 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Status is the status of an operation.
+type Status string
+
+const (
+	// StatusPending is the status of an operation waiting for a worker.
+	StatusPending Status = "pending"
+	// StatusRunning is the status of an operation being processed.
+	StatusRunning Status = "running"
+	// StatusSucceeded is the status of an operation completed successfully.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed is the status of an operation completed with an error.
+	StatusFailed Status = "failed"
+	// StatusCanceled is the status of a canceled operation.
+	StatusCanceled Status = "canceled"
+)
+
+// ErrNotFound is returned when the operation does not exist or has expired.
+var ErrNotFound = errors.New("operation not found")
+
+// Operation is a long-running operation.
+type Operation struct {
+	ID        string    `json:"id"`
+	Status    Status    `json:"status"`
+	Progress  float64   `json:"progress"`
+	Result    any       `json:"result,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+}
+
+// Done returns true if the operation is completed.
+func (o Operation) Done() bool {
+	return o.Status == StatusSucceeded || o.Status == StatusFailed || o.Status == StatusCanceled
+}
+
+// Store stores the operations.
+type Store interface {
+	// Create stores a new operation.
+	Create(ctx context.Context, op Operation) error
+
+	// Get returns the operation with the given ID.
+	// It returns ErrNotFound if the operation does not exist or has expired.
+	Get(ctx context.Context, id string) (Operation, error)
+
+	// Update updates the operation with the given ID.
+	// It returns ErrNotFound if the operation does not exist or has expired.
+	Update(ctx context.Context, id string, fn func(*Operation)) error
+
+	// DeleteExpired deletes the operations expired at the given time.
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
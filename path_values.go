@@ -0,0 +1,78 @@
+package ki
+
+import (
+	"maps"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// wildcardRegexp matches the wildcards of a pattern.
+var wildcardRegexp = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// stripPrefix returns a middleware that strips the given prefix from the request path.
+// Prefixes containing wildcards are stripped segment by segment and their values are kept for the sub-router.
+func stripPrefix(prefix string) func(http.Handler) http.Handler {
+	if !strings.Contains(prefix, "{") {
+		return func(next http.Handler) http.Handler {
+			return http.StripPrefix(prefix, next)
+		}
+	}
+
+	segments := strings.Count(prefix, "/")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			index := 0
+
+			for range segments {
+				i := strings.Index(path[index+1:], "/")
+				if i < 0 {
+					http.NotFound(w, r)
+					return
+				}
+
+				index += i + 1
+			}
+
+			next.ServeHTTP(w, withPath(withPrefixPathValues(r, prefix), path[index:]))
+		})
+	}
+}
+
+// withPrefixPathValues returns a shallow copy of the request carrying the values of the prefix wildcards.
+func withPrefixPathValues(r *http.Request, prefix string) *http.Request {
+	matches := wildcardRegexp.FindAllStringSubmatch(prefix, -1)
+	if len(matches) == 0 {
+		return r
+	}
+
+	values := map[string]string{}
+
+	if inherited, ok := r.Context().Value(pathValuesContextKey).(map[string]string); ok {
+		maps.Copy(values, inherited)
+	}
+
+	for _, match := range matches {
+		values[match[1]] = r.PathValue(match[1])
+	}
+
+	return r.WithContext(setPathValues(r.Context(), values))
+}
+
+// inheritPathValues returns a handler that restores the path values matched by the parent routers.
+func inheritPathValues(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values, ok := r.Context().Value(pathValuesContextKey).(map[string]string)
+		if ok {
+			for name, value := range values {
+				if r.PathValue(name) == "" {
+					r.SetPathValue(name, value)
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,154 @@
+package ki
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// patterns caches the parsed patterns by pattern.
+var patterns sync.Map
+
+// pathPattern is a parsed route pattern.
+type pathPattern struct {
+	host     string
+	segments []patternSegment
+	// subtree is true if the pattern matches the paths below its segments, i.e. it ends with a slash or a
+	// "{name...}" wildcard.
+	subtree bool
+	// rest is the name of the "{name...}" wildcard matching the remaining segments.
+	rest string
+}
+
+// patternSegment is a segment of a pattern, either a literal or a wildcard.
+type patternSegment struct {
+	literal    string
+	name       string
+	constraint *regexp.Regexp
+}
+
+// parsePattern returns the parsed pattern.
+// The pattern is a path optionally preceded by a host, e.g. "example.com/posts/{id}", without method.
+// It panics if a constraint is not a valid regular expression.
+func parsePattern(pattern string) pathPattern {
+	if p, ok := patterns.Load(pattern); ok {
+		return p.(pathPattern)
+	}
+
+	p := pathPattern{}
+	path := pattern
+
+	if i := strings.Index(pattern, "/"); i > 0 {
+		p.host, path = pattern[:i], pattern[i:]
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	last := parts[len(parts)-1]
+
+	switch {
+	case last == "{$}":
+		parts[len(parts)-1] = ""
+	case last == "":
+		parts = parts[:len(parts)-1]
+		p.subtree = true
+	case strings.HasPrefix(last, "{") && strings.HasSuffix(last, "...}"):
+		parts = parts[:len(parts)-1]
+		p.subtree = true
+		p.rest = strings.TrimSuffix(strings.TrimPrefix(last, "{"), "...}")
+	}
+
+	for _, part := range parts {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			p.segments = append(p.segments, patternSegment{literal: part})
+			continue
+		}
+
+		name, expr, constrained := strings.Cut(part[1:len(part)-1], ":")
+
+		segment := patternSegment{name: name}
+
+		if constrained {
+			segment.constraint = regexp.MustCompile("^(?:" + expr + ")$")
+		}
+
+		p.segments = append(p.segments, segment)
+	}
+
+	patterns.Store(pattern, p)
+
+	return p
+}
+
+// match returns the path values if the unescaped segments of a path match the pattern.
+func (p pathPattern) match(segments []string) (map[string]string, bool) {
+	if len(segments) < len(p.segments) || (!p.subtree && len(segments) != len(p.segments)) {
+		return nil, false
+	}
+
+	if p.subtree && len(segments) == len(p.segments) {
+		return nil, false
+	}
+
+	values := map[string]string{}
+
+	for i, segment := range p.segments {
+		switch {
+		case segment.name == "":
+			if segments[i] != segment.literal {
+				return nil, false
+			}
+		case segments[i] == "":
+			return nil, false
+		case segment.constraint != nil && !segment.constraint.MatchString(segments[i]):
+			return nil, false
+		default:
+			values[segment.name] = segments[i]
+		}
+	}
+
+	if p.rest != "" {
+		values[p.rest] = strings.Join(segments[len(p.segments):], "/")
+	}
+
+	return values, true
+}
+
+// compare compares the specificity of the patterns: a literal segment is more specific than a constrained wildcard,
+// which is more specific than a wildcard, a pattern matching a single path is more specific than a subtree and a
+// pattern bound to a host is more specific than the same pattern without host.
+func (p pathPattern) compare(other pathPattern) int {
+	for i := range min(len(p.segments), len(other.segments)) {
+		if c := p.segments[i].rank() - other.segments[i].rank(); c != 0 {
+			return c
+		}
+	}
+
+	if c := len(p.segments) - len(other.segments); c != 0 {
+		return c
+	}
+
+	switch {
+	case !p.subtree && other.subtree:
+		return 1
+	case p.subtree && !other.subtree:
+		return -1
+	case p.host != "" && other.host == "":
+		return 1
+	case p.host == "" && other.host != "":
+		return -1
+	default:
+		return 0
+	}
+}
+
+// rank returns the specificity of the segment.
+func (s patternSegment) rank() int {
+	switch {
+	case s.name == "":
+		return 2
+	case s.constraint != nil:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,56 @@
+package ki
+
+import (
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		host     string
+		segments int
+		subtree  bool
+		rest     string
+	}{
+		{pattern: "/", subtree: true},
+		{pattern: "/{$}", segments: 1},
+		{pattern: "/posts", segments: 1},
+		{pattern: "/posts/", segments: 1, subtree: true},
+		{pattern: "/posts/{id:[0-9]+}", segments: 2},
+		{pattern: "/files/{path...}", segments: 1, subtree: true, rest: "path"},
+		{pattern: "example.com/posts", host: "example.com", segments: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p := parsePattern(tt.pattern)
+
+			if p.host != tt.host || len(p.segments) != tt.segments || p.subtree != tt.subtree || p.rest != tt.rest {
+				t.Fatalf("Unexpected pattern: got=%+v", p)
+			}
+		})
+	}
+}
+
+func TestPathPattern_Compare(t *testing.T) {
+	tests := []struct {
+		more string
+		less string
+	}{
+		{more: "/posts/new", less: "/posts/{id}"},
+		{more: "/posts/{id:[0-9]+}", less: "/posts/{id}"},
+		{more: "/posts/{id}", less: "/posts/"},
+		{more: "/posts/{id}", less: "/posts/{path...}"},
+		{more: "example.com/posts", less: "/posts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.more, func(t *testing.T) {
+			more, less := parsePattern(tt.more), parsePattern(tt.less)
+
+			if more.compare(less) <= 0 || less.compare(more) >= 0 {
+				t.Fatalf("Unexpected order: got=%s <= %s", tt.more, tt.less)
+			}
+		})
+	}
+}
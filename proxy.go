@@ -0,0 +1,410 @@
+package ki
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Balancing is the load balancing strategy of a proxy.
+type Balancing int
+
+const (
+	// RoundRobin sends the requests to the targets in turn.
+	RoundRobin Balancing = iota
+	// LeastConnections sends the requests to the target with the fewest active requests.
+	LeastConnections
+)
+
+// ErrNoTarget is returned when no healthy target is available.
+var ErrNoTarget = errors.New("no healthy proxy target")
+
+// ProxyOptions configures a proxy.
+type ProxyOptions struct {
+	// Balancing is the load balancing strategy, RoundRobin by default.
+	Balancing Balancing
+	// Retries is the number of retries on another target of the idempotent requests failing to reach a target,
+	// 2 by default. A negative value disables the retries.
+	// Requests with a body are only retried if the body can be replayed with GetBody.
+	Retries int
+	// MaxFails is the number of consecutive failures after which a target is considered unhealthy, 3 by default.
+	// A negative value disables the passive health checks.
+	MaxFails int
+	// FailTimeout is the duration a target is considered unhealthy after MaxFails failures, 10 seconds by default.
+	FailTimeout time.Duration
+	// HealthCheckPath is the path requested by the active health checks, "/" by default.
+	HealthCheckPath string
+	// HealthCheckInterval is the interval between the active health checks, 10 seconds by default.
+	HealthCheckInterval time.Duration
+	// RequestIDHeader is the header carrying the request ID upstream, "X-Request-Id" by default.
+	RequestIDHeader string
+	// Transport is the transport used to reach the targets, http.DefaultTransport by default.
+	Transport http.RoundTripper
+}
+
+// ReverseProxy is a load balancing reverse proxy.
+type ReverseProxy struct {
+	options ProxyOptions
+	targets []*proxyTarget
+	next    atomic.Uint64
+	proxy   *httputil.ReverseProxy
+}
+
+// proxyTarget is a target of a proxy.
+type proxyTarget struct {
+	url    *url.URL
+	active atomic.Int64
+
+	mu        sync.Mutex
+	fails     int
+	downUntil time.Time
+	unhealthy bool
+}
+
+// Proxy returns a new reverse proxy to the given targets with the default options.
+func Proxy(targets ...*url.URL) *ReverseProxy {
+	return NewProxy(ProxyOptions{}, targets...)
+}
+
+// NewProxy returns a new reverse proxy to the given targets.
+//
+// The proxy is meant to be mounted: the path stripped by Mount is reported upstream in the X-Forwarded-Prefix header
+// along with the usual X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto headers and the request ID.
+// Failing targets are taken out of rotation for FailTimeout, see HealthCheck for the active health checks.
+// It panics if there is no target.
+func NewProxy(options ProxyOptions, targets ...*url.URL) *ReverseProxy {
+	if len(targets) == 0 {
+		panic("proxy without target")
+	}
+
+	if options.Retries == 0 {
+		options.Retries = 2
+	}
+
+	if options.MaxFails == 0 {
+		options.MaxFails = 3
+	}
+
+	if options.FailTimeout <= 0 {
+		options.FailTimeout = 10 * time.Second
+	}
+
+	if options.HealthCheckPath == "" {
+		options.HealthCheckPath = "/"
+	}
+
+	if options.HealthCheckInterval <= 0 {
+		options.HealthCheckInterval = 10 * time.Second
+	}
+
+	if options.RequestIDHeader == "" {
+		options.RequestIDHeader = "X-Request-Id"
+	}
+
+	if options.Transport == nil {
+		options.Transport = http.DefaultTransport
+	}
+
+	p := &ReverseProxy{
+		options: options,
+	}
+
+	for _, target := range targets {
+		p.targets = append(p.targets, &proxyTarget{url: target})
+	}
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite:      p.rewrite,
+		Transport:    roundTripperFunc(p.roundTrip),
+		ErrorHandler: p.errorHandler,
+	}
+
+	return p
+}
+
+// ServeHTTP proxies the request to a healthy target.
+func (p *ReverseProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.proxy.ServeHTTP(w, r)
+}
+
+// HealthCheck requests the HealthCheckPath of every target every HealthCheckInterval until the context is canceled.
+// A target is healthy as long as it responds with a 2xx status.
+func (p *ReverseProxy) HealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(p.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.checkHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth checks the health of every target once.
+func (p *ReverseProxy) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.options.HealthCheckInterval)
+	defer cancel()
+
+	client := &http.Client{Transport: p.options.Transport}
+
+	var wg sync.WaitGroup
+
+	for _, target := range p.targets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			healthy := false
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.url.JoinPath(p.options.HealthCheckPath).String(), nil)
+			if err == nil {
+				res, err := client.Do(req)
+				if err == nil {
+					_, _ = io.Copy(io.Discard, res.Body)
+					_ = res.Body.Close()
+
+					healthy = res.StatusCode >= 200 && res.StatusCode < 300
+				}
+			}
+
+			target.mu.Lock()
+			target.unhealthy = !healthy
+			target.mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+}
+
+// rewrite sets the forwarded headers of the outgoing request.
+// The target is picked by the transport, so that a retry can pick another one.
+func (p *ReverseProxy) rewrite(pr *httputil.ProxyRequest) {
+	pr.Out.Header["X-Forwarded-For"] = pr.In.Header["X-Forwarded-For"]
+	pr.SetXForwarded()
+
+	// The prefix sent by the client is not trusted, like the headers replaced by SetXForwarded.
+	pr.Out.Header.Del("X-Forwarded-Prefix")
+
+	if u, err := url.ParseRequestURI(pr.In.RequestURI); err == nil {
+		prefix, found := strings.CutSuffix(u.Path, pr.In.URL.Path)
+		if found && prefix != "" {
+			pr.Out.Header.Set("X-Forwarded-Prefix", prefix)
+		}
+	}
+
+	if requestID := GetRequestID(pr.In.Context()); requestID != "" {
+		pr.Out.Header.Set(p.options.RequestIDHeader, requestID)
+	}
+}
+
+// roundTrip sends the request to a healthy target, retrying the idempotent requests on another target.
+func (p *ReverseProxy) roundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if p.options.Retries > 0 && isReplayable(req) {
+		attempts += p.options.Retries
+	}
+
+	tried := map[*proxyTarget]bool{}
+
+	err := ErrNoTarget
+
+	for attempt := range attempts {
+		target := p.pick(tried)
+		if target == nil {
+			break
+		}
+
+		tried[target] = true
+
+		body := req.Body
+		if attempt > 0 && req.GetBody != nil {
+			body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		target.active.Add(1)
+
+		var res *http.Response
+
+		res, err = p.options.Transport.RoundTrip(target.request(req, body))
+		if err != nil {
+			target.active.Add(-1)
+			target.fail(p.options)
+
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		switch res.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			target.fail(p.options)
+		default:
+			target.succeed()
+		}
+
+		if res.StatusCode == http.StatusSwitchingProtocols {
+			// The upgraded connection requires the original body.
+			target.active.Add(-1)
+		} else {
+			res.Body = &proxyBody{ReadCloser: res.Body, target: target}
+		}
+
+		return res, nil
+	}
+
+	return nil, err
+}
+
+// pick returns the next healthy target not yet tried, or nil if there is none.
+func (p *ReverseProxy) pick(tried map[*proxyTarget]bool) *proxyTarget {
+	now := time.Now()
+	n := len(p.targets)
+	start := int((p.next.Add(1) - 1) % uint64(n))
+
+	var best *proxyTarget
+
+	for i := range n {
+		target := p.targets[(start+i)%n]
+
+		if tried[target] || !target.available(now) {
+			continue
+		}
+
+		if p.options.Balancing == RoundRobin {
+			return target
+		}
+
+		if best == nil || target.active.Load() < best.active.Load() {
+			best = target
+		}
+	}
+
+	return best
+}
+
+// errorHandler responds 503 when no target is available and 502 when the target cannot be reached.
+func (p *ReverseProxy) errorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadGateway
+
+	switch {
+	case errors.Is(err, ErrNoTarget):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	}
+
+	GetLogger(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "proxy error", slog.String("error", err.Error()))
+
+	http.Error(w, http.StatusText(status), status)
+}
+
+// request returns the request to send to the target.
+func (t *proxyTarget) request(req *http.Request, body io.ReadCloser) *http.Request {
+	out := req.Clone(req.Context())
+	out.Body = body
+	out.Host = ""
+
+	base := *t.url
+	if base.Path == "" {
+		// The joined path of a target without path would have no leading slash.
+		base.Path = "/"
+	}
+
+	out.URL = base.JoinPath(req.URL.EscapedPath())
+	out.URL.RawQuery = req.URL.RawQuery
+
+	if t.url.RawQuery != "" {
+		out.URL.RawQuery = t.url.RawQuery
+		if req.URL.RawQuery != "" {
+			out.URL.RawQuery += "&" + req.URL.RawQuery
+		}
+	}
+
+	return out
+}
+
+// available returns true if the target is healthy.
+func (t *proxyTarget) available(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return !t.unhealthy && !now.Before(t.downUntil)
+}
+
+// fail records a failure and takes the target out of rotation after too many consecutive failures.
+func (t *proxyTarget) fail(options ProxyOptions) {
+	if options.MaxFails < 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fails++
+
+	if t.fails >= options.MaxFails {
+		t.fails = 0
+		t.downUntil = time.Now().Add(options.FailTimeout)
+	}
+}
+
+// succeed resets the consecutive failures.
+func (t *proxyTarget) succeed() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fails = 0
+}
+
+// proxyBody releases the connection of the target when the response body is closed.
+type proxyBody struct {
+	io.ReadCloser
+	target *proxyTarget
+	once   sync.Once
+}
+
+// Close closes the body.
+func (b *proxyBody) Close() error {
+	b.once.Do(func() {
+		b.target.active.Add(-1)
+	})
+
+	return b.ReadCloser.Close()
+}
+
+// isReplayable returns true if the request is idempotent and its body can be sent again.
+func isReplayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// roundTripperFunc is an adapter to use a function as a RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
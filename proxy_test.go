@@ -0,0 +1,294 @@
+package ki
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newProxyBackend(t *testing.T, name string) (*httptest.Server, *url.URL) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Backend", name)
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+
+	return server, u
+}
+
+func newDeadBackend(t *testing.T) *url.URL {
+	t.Helper()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	return u
+}
+
+func proxyRequest(p http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	rec := httptest.NewRecorder()
+
+	p.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestProxy_Mount(t *testing.T) {
+	var upstream *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL + "/base")
+
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(SetRequestID(r.Context(), "req-1")))
+		})
+	})
+	mux.Mount("/api", Proxy(target))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/1?expand=true", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Body.String(); got != "/base/users/1?expand=true" {
+		t.Fatalf("expected upstream URI %q, got %q", "/base/users/1?expand=true", got)
+	}
+
+	headers := map[string]string{
+		"X-Forwarded-For":    "203.0.113.7, 192.0.2.1",
+		"X-Forwarded-Host":   "example.com",
+		"X-Forwarded-Proto":  "http",
+		"X-Forwarded-Prefix": "/api",
+		"X-Request-Id":       "req-1",
+	}
+
+	for name, expected := range headers {
+		if got := upstream.Header.Get(name); got != expected {
+			t.Errorf("expected %s %q, got %q", name, expected, got)
+		}
+	}
+
+	if upstream.Host != target.Host {
+		t.Errorf("expected host %q, got %q", target.Host, upstream.Host)
+	}
+}
+
+func TestProxy_TargetWithoutPath(t *testing.T) {
+	_, target := newProxyBackend(t, "a")
+
+	rec := proxyRequest(Proxy(target), http.MethodGet, "/users/1")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if got := rec.Body.String(); got != "/users/1" {
+		t.Fatalf("expected upstream URI %q, got %q", "/users/1", got)
+	}
+}
+
+func TestProxy_SpoofedPrefix(t *testing.T) {
+	var upstream *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream = r
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Forwarded-Prefix", "/admin")
+	rec := httptest.NewRecorder()
+
+	Proxy(target).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if got := upstream.Header.Get("X-Forwarded-Prefix"); got != "" {
+		t.Fatalf("expected no X-Forwarded-Prefix, got %q", got)
+	}
+}
+
+func TestProxy_RoundRobin(t *testing.T) {
+	_, a := newProxyBackend(t, "a")
+	_, b := newProxyBackend(t, "b")
+
+	p := Proxy(a, b)
+
+	got := []string{}
+	for range 4 {
+		got = append(got, proxyRequest(p, http.MethodGet, "/").Header().Get("X-Backend"))
+	}
+
+	if strings.Join(got, ",") != "a,b,a,b" {
+		t.Fatalf("expected backends a,b,a,b, got %v", got)
+	}
+}
+
+func TestProxy_LeastConnections(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Header().Set("X-Backend", "slow")
+	}))
+	defer slow.Close()
+
+	a, _ := url.Parse(slow.URL)
+	_, b := newProxyBackend(t, "b")
+
+	p := NewProxy(ProxyOptions{Balancing: LeastConnections}, a, b)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		proxyRequest(p, http.MethodGet, "/")
+	}()
+
+	<-started
+
+	for i := range 3 {
+		if got := proxyRequest(p, http.MethodGet, "/").Header().Get("X-Backend"); got != "b" {
+			t.Errorf("request %d: expected backend b, got %q", i, got)
+		}
+	}
+
+	close(release)
+	wg.Wait()
+}
+
+func TestProxy_Retries(t *testing.T) {
+	_, live := newProxyBackend(t, "live")
+
+	p := NewProxy(ProxyOptions{MaxFails: -1}, newDeadBackend(t), live)
+
+	for i := range 4 {
+		if rec := proxyRequest(p, http.MethodGet, "/"); rec.Code != http.StatusOK {
+			t.Fatalf("GET %d: expected status 200, got %d", i, rec.Code)
+		}
+	}
+
+	failed := 0
+
+	for range 4 {
+		if rec := proxyRequest(p, http.MethodPost, "/"); rec.Code == http.StatusBadGateway {
+			failed++
+		}
+	}
+
+	if failed != 2 {
+		t.Fatalf("expected 2 failed POST without retry, got %d", failed)
+	}
+}
+
+func TestProxy_PassiveHealthCheck(t *testing.T) {
+	_, live := newProxyBackend(t, "live")
+
+	p := NewProxy(ProxyOptions{Retries: -1, MaxFails: 1, FailTimeout: time.Hour}, newDeadBackend(t), live)
+
+	if rec := proxyRequest(p, http.MethodGet, "/"); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", rec.Code)
+	}
+
+	for i := range 4 {
+		if rec := proxyRequest(p, http.MethodGet, "/"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i, rec.Code)
+		}
+	}
+}
+
+func TestProxy_ActiveHealthCheck(t *testing.T) {
+	var mu sync.Mutex
+
+	healthy := false
+
+	sick := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Backend", "sick")
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/health" && !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer sick.Close()
+
+	a, _ := url.Parse(sick.URL)
+	_, b := newProxyBackend(t, "b")
+
+	p := NewProxy(ProxyOptions{HealthCheckPath: "/health"}, a, b)
+	p.checkHealth(context.Background())
+
+	for i := range 4 {
+		if got := proxyRequest(p, http.MethodGet, "/").Header().Get("X-Backend"); got != "b" {
+			t.Fatalf("request %d: expected backend b, got %q", i, got)
+		}
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+
+	p.checkHealth(context.Background())
+
+	seen := map[string]bool{}
+	for range 4 {
+		seen[proxyRequest(p, http.MethodGet, "/").Header().Get("X-Backend")] = true
+	}
+
+	if !seen["sick"] || !seen["b"] {
+		t.Fatalf("expected both backends after recovery, got %v", seen)
+	}
+}
+
+func TestProxy_NoTarget(t *testing.T) {
+	p := NewProxy(ProxyOptions{Retries: -1, MaxFails: 1, FailTimeout: time.Hour}, newDeadBackend(t))
+
+	if rec := proxyRequest(p, http.MethodGet, "/"); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", rec.Code)
+	}
+
+	if rec := proxyRequest(p, http.MethodGet, "/"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rec.Code)
+	}
+}
+
+func TestProxy_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for proxy without target")
+		}
+	}()
+
+	Proxy()
+}
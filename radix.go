@@ -0,0 +1,378 @@
+package ki
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// radixMatcher is a matcher backed by a radix tree of path segments.
+// Static segments take precedence over constrained wildcards, which take precedence over the other wildcards.
+type radixMatcher struct {
+	root  *radixNode
+	hosts map[string]*radixNode
+}
+
+// radixNode is a node of the radix tree.
+type radixNode struct {
+	static  map[string]*radixNode
+	params  []*radixParam
+	entries map[string]*radixEntry
+	subtree map[string]*radixEntry
+}
+
+// radixParam is a wildcard edge of the radix tree.
+type radixParam struct {
+	segment    string
+	name       string
+	constraint *regexp.Regexp
+	node       *radixNode
+}
+
+// radixEntry is a registered pattern.
+type radixEntry struct {
+	pattern  string
+	wildcard string
+	handler  http.Handler
+}
+
+// radixValue is a matched path value.
+type radixValue struct {
+	name  string
+	value string
+}
+
+// newRadixMatcher returns a new radixMatcher.
+func newRadixMatcher() *radixMatcher {
+	return &radixMatcher{
+		root:  newRadixNode(),
+		hosts: map[string]*radixNode{},
+	}
+}
+
+// newRadixNode returns a new radixNode.
+func newRadixNode() *radixNode {
+	return &radixNode{
+		static:  map[string]*radixNode{},
+		entries: map[string]*radixEntry{},
+		subtree: map[string]*radixEntry{},
+	}
+}
+
+// Handle registers the handler for the given pattern.
+// It panics if the pattern is invalid or already registered.
+func (m *radixMatcher) Handle(pattern string, handler http.Handler) {
+	method, host, p := splitPattern(pattern)
+
+	node := m.root
+
+	if host != "" {
+		if _, ok := m.hosts[host]; !ok {
+			m.hosts[host] = newRadixNode()
+		}
+
+		node = m.hosts[host]
+	}
+
+	segments := strings.Split(p[1:], "/")
+	last := segments[len(segments)-1]
+
+	wildcard := ""
+
+	switch {
+	case last == "{$}":
+		segments[len(segments)-1] = ""
+	case last == "" || (strings.HasPrefix(last, "{") && strings.HasSuffix(last, "...}")):
+		segments = segments[:len(segments)-1]
+		wildcard = strings.TrimSuffix(strings.TrimPrefix(last, "{"), "...}")
+	}
+
+	for _, segment := range segments {
+		if segment == "{$}" || strings.HasSuffix(segment, "...}") {
+			panic(fmt.Sprintf("invalid pattern %q: %s must be the last segment", pattern, segment))
+		}
+
+		node = node.child(segment)
+	}
+
+	entries := node.entries
+	if wildcard != "" || last == "" {
+		entries = node.subtree
+	}
+
+	if existing, ok := entries[method]; ok {
+		panic(fmt.Sprintf("pattern %q conflicts with pattern %q", pattern, existing.pattern))
+	}
+
+	entries[method] = &radixEntry{
+		pattern:  pattern,
+		wildcard: wildcard,
+		handler:  handler,
+	}
+}
+
+// Handler returns the handler and the pattern matching the request.
+func (m *radixMatcher) Handler(r *http.Request) (http.Handler, string) {
+	h, pattern, _ := m.find(r)
+
+	return h, pattern
+}
+
+// ServeHTTP dispatches the request to the handler matching the request.
+func (m *radixMatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, pattern, values := m.find(r)
+
+	r.Pattern = pattern
+
+	for _, v := range values {
+		r.SetPathValue(v.name, v.value)
+	}
+
+	h.ServeHTTP(w, r)
+}
+
+// find returns the handler, the pattern and the path values matching the request.
+func (m *radixMatcher) find(r *http.Request) (http.Handler, string, []radixValue) {
+	if r.Method != http.MethodConnect {
+		if clean := cleanPath(r.URL.Path); clean != r.URL.Path {
+			return http.RedirectHandler((&url.URL{Path: clean, RawQuery: r.URL.RawQuery}).String(), http.StatusMovedPermanently), "", nil
+		}
+	}
+
+	trees := []*radixNode{m.root}
+
+	if len(m.hosts) > 0 {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+
+		if node, ok := m.hosts[host]; ok {
+			trees = []*radixNode{node, m.root}
+		}
+	}
+
+	segments := splitPath(r.URL.EscapedPath())
+
+	for _, tree := range trees {
+		if entry, values := tree.lookup(segments, r.Method, nil); entry != nil {
+			return entry.handler, entry.pattern, values
+		}
+	}
+
+	allowed := map[string]bool{}
+
+	for _, tree := range trees {
+		tree.allow(segments, allowed)
+	}
+
+	if len(allowed) > 0 {
+		if allowed[http.MethodGet] {
+			allowed[http.MethodHead] = true
+		}
+
+		methods := []string{}
+		for method := range allowed {
+			methods = append(methods, method)
+		}
+
+		slices.Sort(methods)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}), "", nil
+	}
+
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		for _, tree := range trees {
+			if entry, _ := tree.lookup(append(segments, ""), r.Method, nil); entry != nil {
+				return http.RedirectHandler((&url.URL{Path: r.URL.Path + "/", RawQuery: r.URL.RawQuery}).String(), http.StatusMovedPermanently), "", nil
+			}
+		}
+	}
+
+	return http.NotFoundHandler(), "", nil
+}
+
+// child returns the child node for the given pattern segment, creating it if necessary.
+func (n *radixNode) child(segment string) *radixNode {
+	if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+		if _, ok := n.static[segment]; !ok {
+			n.static[segment] = newRadixNode()
+		}
+
+		return n.static[segment]
+	}
+
+	for _, param := range n.params {
+		if param.segment == segment {
+			return param.node
+		}
+	}
+
+	name, expr, constrained := strings.Cut(segment[1:len(segment)-1], ":")
+
+	param := &radixParam{
+		segment: segment,
+		name:    name,
+		node:    newRadixNode(),
+	}
+
+	if constrained {
+		param.constraint = regexp.MustCompile("^(?:" + expr + ")$")
+	}
+
+	n.params = append(n.params, param)
+
+	slices.SortStableFunc(n.params, func(a, b *radixParam) int {
+		switch {
+		case a.constraint != nil && b.constraint == nil:
+			return -1
+		case a.constraint == nil && b.constraint != nil:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return param.node
+}
+
+// lookup returns the entry and the path values matching the segments.
+func (n *radixNode) lookup(segments []string, method string, values []radixValue) (*radixEntry, []radixValue) {
+	if len(segments) == 0 {
+		return findEntry(n.entries, method), values
+	}
+
+	segment := segments[0]
+
+	if child, ok := n.static[segment]; ok {
+		if entry, v := child.lookup(segments[1:], method, values); entry != nil {
+			return entry, v
+		}
+	}
+
+	if segment != "" {
+		for _, param := range n.params {
+			if param.constraint != nil && !param.constraint.MatchString(segment) {
+				continue
+			}
+
+			if entry, v := param.node.lookup(segments[1:], method, append(values, radixValue{param.name, segment})); entry != nil {
+				return entry, v
+			}
+		}
+	}
+
+	entry := findEntry(n.subtree, method)
+	if entry == nil {
+		return nil, values
+	}
+
+	if entry.wildcard != "" {
+		values = append(values, radixValue{entry.wildcard, strings.Join(segments, "/")})
+	}
+
+	return entry, values
+}
+
+// allow collects the methods of the entries matching the segments.
+func (n *radixNode) allow(segments []string, allowed map[string]bool) {
+	if len(segments) == 0 {
+		for method := range n.entries {
+			allowed[method] = true
+		}
+
+		return
+	}
+
+	segment := segments[0]
+
+	if child, ok := n.static[segment]; ok {
+		child.allow(segments[1:], allowed)
+	}
+
+	if segment != "" {
+		for _, param := range n.params {
+			if param.constraint == nil || param.constraint.MatchString(segment) {
+				param.node.allow(segments[1:], allowed)
+			}
+		}
+	}
+
+	for method := range n.subtree {
+		allowed[method] = true
+	}
+}
+
+// findEntry returns the entry for the given method.
+func findEntry(entries map[string]*radixEntry, method string) *radixEntry {
+	if entry, ok := entries[method]; ok {
+		return entry
+	}
+
+	if method == http.MethodHead {
+		if entry, ok := entries[http.MethodGet]; ok {
+			return entry
+		}
+	}
+
+	return entries[""]
+}
+
+// splitPattern splits the pattern into its method, host and path.
+// It panics if the pattern is invalid.
+func splitPattern(pattern string) (string, string, string) {
+	method := ""
+	rest := pattern
+
+	if before, after, found := strings.Cut(pattern, " "); found {
+		method = before
+		rest = strings.TrimLeft(after, " \t")
+	}
+
+	i := strings.Index(rest, "/")
+	if i < 0 {
+		panic(fmt.Sprintf("invalid pattern %q: missing path", pattern))
+	}
+
+	return method, rest[:i], rest[i:]
+}
+
+// splitPath splits the escaped path into its unescaped segments.
+func splitPath(escaped string) []string {
+	segments := strings.Split(strings.TrimPrefix(escaped, "/"), "/")
+
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	return segments
+}
+
+// cleanPath returns the canonical path for p, eliminating . and .. elements.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	if p[0] != '/' {
+		p = "/" + p
+	}
+
+	np := path.Clean(p)
+
+	if p[len(p)-1] == '/' && np != "/" {
+		np += "/"
+	}
+
+	return np
+}
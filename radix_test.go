@@ -0,0 +1,98 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRadixMatcher_Match(t *testing.T) {
+	pattern := func(p string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(p + " " + r.PathValue("id") + r.PathValue("rest")))
+		})
+	}
+
+	patterns := []string{
+		"GET /{$}",
+		"GET /posts/new",
+		"GET /posts/{id:[0-9]+}",
+		"GET /posts/{id}",
+		"DELETE /posts/{id}",
+		"/files/{rest...}",
+		"/static/",
+		"GET api.example.org/posts/new",
+	}
+
+	m := newRadixMatcher()
+	for _, p := range patterns {
+		m.Handle(p, pattern(p))
+	}
+
+	tests := []struct {
+		method string
+		target string
+		code   int
+		body   string
+	}{
+		{method: http.MethodGet, target: "/", code: http.StatusOK, body: "GET /{$} "},
+		{method: http.MethodGet, target: "/posts/new", code: http.StatusOK, body: "GET /posts/new "},
+		{method: http.MethodGet, target: "/posts/42", code: http.StatusOK, body: "GET /posts/{id:[0-9]+} 42"},
+		{method: http.MethodGet, target: "/posts/foo", code: http.StatusOK, body: "GET /posts/{id} foo"},
+		{method: http.MethodHead, target: "/posts/foo", code: http.StatusOK},
+		{method: http.MethodDelete, target: "/posts/42", code: http.StatusOK, body: "DELETE /posts/{id} 42"},
+		{method: http.MethodGet, target: "/files/a/b%2Fc", code: http.StatusOK, body: "/files/{rest...} a/b/c"},
+		{method: http.MethodPost, target: "/static/css/main.css", code: http.StatusOK, body: "/static/ "},
+		{method: http.MethodGet, target: "http://api.example.org/posts/new", code: http.StatusOK, body: "GET api.example.org/posts/new "},
+		{method: http.MethodPut, target: "/posts/42", code: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, target: "/static", code: http.StatusMovedPermanently},
+		{method: http.MethodGet, target: "/posts/../posts/new", code: http.StatusMovedPermanently},
+		{method: http.MethodGet, target: "/unknown", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			m.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, tt.code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRadixMatcher_MethodNotAllowed(t *testing.T) {
+	m := newRadixMatcher()
+	m.Handle("GET /posts/{id}", http.NotFoundHandler())
+	m.Handle("DELETE /posts/{id}", http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/1", nil)
+	rec := httptest.NewRecorder()
+
+	m.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "DELETE, GET, HEAD" {
+		t.Fatalf("expected Allow header %q, got %q", "DELETE, GET, HEAD", got)
+	}
+}
+
+func TestRadixMatcher_PanicsOnConflict(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on conflicting patterns")
+		}
+	}()
+
+	m := newRadixMatcher()
+	m.Handle("GET /posts/{id}", http.NotFoundHandler())
+	m.Handle("GET /posts/{id}", http.NotFoundHandler())
+}
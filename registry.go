@@ -2,68 +2,137 @@ package ki
 
 import (
 	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 )
 
+// registryMu guards the registries, which can be attached to each other.
+// The routes are usually added at startup while the lookups happen concurrently during the requests.
+var registryMu sync.RWMutex
+
 // Registry is a registry of routes.
+//
+// The names of the routes are qualified by the namespaces of the routers, e.g. "admin.dashboard" for the route
+// "dashboard" of a router with the "admin." name prefix, see WithNamePrefix.
+// Every registry indexes the routes of its whole subtree by qualified name, so that the lookups do not depend on the
+// depth of the tree, and a name can only be used once in a tree.
+// A Registry is safe for concurrent use.
 type Registry struct {
+	parent *Registry
+	// prefix is the prefix of the registry in its parent.
+	prefix string
+	// namespace is the name prefix of the routes added to the registry, used by the relative lookups.
+	namespace string
+
 	routeMap   map[string]Location
 	registries map[string]*Registry
+	// namespaces holds the name prefixes of the child registries by prefix.
+	namespaces map[string]string
+	// childMiddlewares holds the names of the middlewares wrapping the child registries by prefix.
+	childMiddlewares map[string][]string
+
+	// index holds the routes of the registry and its child registries by qualified name.
+	index map[string]indexEntry
+}
+
+// indexEntry is an indexed route.
+type indexEntry struct {
+	// location is the location of the route relatively to the indexing registry.
+	location    Location
+	middlewares []string
+}
+
+// RouteDescription describes a named route of a registry.
+type RouteDescription struct {
+	// Name is the fully qualified name of the route.
+	Name string
+	// Method is the method of the route.
+	Method string
+	// Pattern is the full pattern of the route including the prefixes of the routers.
+	Pattern string
+	// Middlewares are the names of the middlewares of the route, from the outermost to the innermost.
+	Middlewares []string
+}
+
+// Namespace is a node of the namespace tree of the named routes.
+// The names are split on their dots, e.g. "admin.users.show" is the route "show" of the namespace "admin.users".
+type Namespace struct {
+	// Name is the fully qualified name of the namespace, empty for the root.
+	Name string
+	// Routes are the routes of the namespace sorted by name.
+	Routes []RouteDescription
+	// Namespaces are the nested namespaces sorted by name.
+	Namespaces []Namespace
 }
 
 // NewRegistry returns a new Registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		routeMap:   map[string]Location{},
-		registries: map[string]*Registry{},
+		routeMap:         map[string]Location{},
+		registries:       map[string]*Registry{},
+		namespaces:       map[string]string{},
+		childMiddlewares: map[string][]string{},
+		index:            map[string]indexEntry{},
 	}
 }
 
 // Add adds a route to the registry.
-// It panics if the route already exists.
+// It panics if a route with the same qualified name already exists in the tree of the registry.
 func (r *Registry) Add(key, method, pattern string) {
-	_, ok := r.routeMap[key]
+	r.add(key, NewLocation(method, pattern), nil)
+}
 
-	if ok {
-		panic(fmt.Sprintf("Location %s already exists", key))
-	}
+// add adds a route with the names of its middlewares to the registry.
+// It panics if a route with the same qualified name already exists in the tree of the registry.
+func (r *Registry) add(key string, location Location, middlewares []string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	r.addIndex(map[string]indexEntry{
+		key: {location: location, middlewares: middlewares},
+	})
 
-	r.routeMap[key] = NewLocation(method, pattern)
+	r.routeMap[key] = location
 }
 
 // Remove removes a route from the registry.
 func (r *Registry) Remove(key string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := r.routeMap[key]; !ok {
+		return
+	}
+
 	delete(r.routeMap, key)
+
+	r.removeIndex([]string{key})
 }
 
-// Has returns true if the registry has a route with the given key or any of its child registries.
+// Has returns true if the location for the given key exists, see Get.
 func (r *Registry) Has(key string) bool {
-	_, ok := r.routeMap[key]
+	_, ok := r.find(key)
 
-	if !ok {
-		for _, registry := range r.registries {
-			if registry.Has(key) {
-				return true
-			}
-		}
-
-		return false
-	}
-
-	return true
+	return ok
 }
 
 // Get returns the location for the given key.
+//
+// The key is either relative to the namespace of the registry, e.g. "dashboard" in the registry of an "admin."
+// router, or fully qualified, e.g. "admin.dashboard". The relative key takes precedence.
+// The location includes the prefixes of the parent registries.
 // It panics if the location does not exist.
 func (r *Registry) Get(key string) Location {
-	location, ok := r.routeMap[key]
+	location, ok := r.find(key)
 
 	if !ok {
-		for prefix, registry := range r.registries {
-			if registry.Has(key) {
-				return registry.Get(key).WithPrefix(prefix)
-			}
-		}
-
 		panic(fmt.Sprintf("Location %s does not exist", key))
 	}
 
@@ -72,9 +141,303 @@ func (r *Registry) Get(key string) Location {
 
 // Child returns a new child registry with the given prefix.
 func (r *Registry) Child(prefix string) *Registry {
+	return r.child(prefix, nil)
+}
+
+// child returns a new child registry with the given prefix wrapped by the given middlewares.
+func (r *Registry) child(prefix string, middlewares []string) *Registry {
 	registry := NewRegistry()
+	registry.namespace = r.namespace
 
-	r.registries[prefix] = registry
+	r.attach(prefix, "", middlewares, registry)
 
 	return registry
 }
+
+// Attach attaches the given registry as a child registry with the given prefix.
+// It is used to resolve the routes of a router mounted at the prefix.
+// It panics if a route of the registry has the same qualified name as a route of the tree.
+func (r *Registry) Attach(prefix string, registry *Registry) {
+	r.attach(prefix, "", nil, registry)
+}
+
+// attach attaches the given registry with the given prefix, name prefix and middlewares.
+// It panics if a route of the registry has the same qualified name as a route of the tree.
+func (r *Registry) attach(prefix, namespace string, middlewares []string, registry *Registry) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if existing, ok := r.registries[prefix]; ok {
+		r.removeIndex(slices.Collect(maps.Keys(r.qualify(prefix, existing.index))))
+	}
+
+	r.namespaces[prefix] = namespace
+	r.childMiddlewares[prefix] = middlewares
+
+	r.addIndex(r.qualify(prefix, registry.index))
+
+	r.registries[prefix] = registry
+
+	registry.parent = r
+	registry.prefix = prefix
+}
+
+// setNamespace sets the name prefix of the routes added to the registry.
+func (r *Registry) setNamespace(namespace string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	r.namespace = namespace
+}
+
+// Routes returns the named routes of the registry and its child registries sorted by name.
+// The names are fully qualified and the patterns include the prefixes of the parent registries.
+func (r *Registry) Routes() []RouteDescription {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	prefix, namespace := r.base()
+
+	routes := []RouteDescription{}
+
+	for _, name := range slices.Sorted(maps.Keys(r.index)) {
+		entry := r.index[name]
+
+		routes = append(routes, RouteDescription{
+			Name:        namespace + name,
+			Method:      entry.location.Method(),
+			Pattern:     prefix + entry.location.prefix + entry.location.Pattern(),
+			Middlewares: entry.middlewares,
+		})
+	}
+
+	return routes
+}
+
+// Match returns the fully qualified name and the path parameters of the named route matching the method and the URL,
+// the inverse of Location.URL.
+// The URL path includes the prefixes of the parent registries and the routes bound to a host only match the URLs of
+// that host. When several routes match, the most specific pattern wins, e.g. "/posts/new" over "/posts/{id}".
+func (r *Registry) Match(method string, u *url.URL) (string, map[string]string, bool) {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return "", nil, false
+	}
+
+	segments := splitPath(path)
+
+	var (
+		name    string
+		params  map[string]string
+		matched pathPattern
+		found   bool
+	)
+
+	for _, route := range r.Routes() {
+		if route.Method != "" && route.Method != method && (method != http.MethodHead || route.Method != http.MethodGet) {
+			continue
+		}
+
+		pattern := parsePattern(route.Pattern)
+
+		if pattern.host != "" && pattern.host != u.Hostname() {
+			continue
+		}
+
+		values, ok := pattern.match(segments)
+		if !ok || (found && pattern.compare(matched) <= 0) {
+			continue
+		}
+
+		name, params, matched, found = route.Name, values, pattern, true
+	}
+
+	return name, params, found
+}
+
+// Tree returns the namespace tree of the named routes of the registry and its child registries.
+func (r *Registry) Tree() Namespace {
+	root := &Namespace{}
+
+	for _, route := range r.Routes() {
+		node := root
+
+		segments := strings.Split(route.Name, ".")
+
+		for i := range len(segments) - 1 {
+			name := strings.Join(segments[:i+1], ".")
+
+			index := slices.IndexFunc(node.Namespaces, func(n Namespace) bool {
+				return n.Name == name
+			})
+
+			if index < 0 {
+				node.Namespaces = append(node.Namespaces, Namespace{Name: name})
+				index = len(node.Namespaces) - 1
+			}
+
+			node = &node.Namespaces[index]
+		}
+
+		node.Routes = append(node.Routes, route)
+	}
+
+	sortNamespaces(root)
+
+	return *root
+}
+
+// find returns the location for the given relative or fully qualified key.
+func (r *Registry) find(key string) (Location, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	keys := []string{key}
+	if r.namespace != "" {
+		keys = []string{r.namespace + key, key}
+	}
+
+	for _, k := range keys {
+		if entry, ok := r.index[k]; ok {
+			return r.absolute(entry.location), true
+		}
+	}
+
+	root := r
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	entry, ok := root.index[key]
+
+	return entry.location, ok
+}
+
+// addIndex indexes the given entries in the registry and its parents.
+// It panics before indexing anything if a qualified name already exists.
+func (r *Registry) addIndex(entries map[string]indexEntry) {
+	levels := []map[string]indexEntry{}
+
+	for registry := r; registry != nil; registry = registry.parent {
+		for name := range entries {
+			if _, ok := registry.index[name]; ok {
+				panic(fmt.Sprintf("Location %s already exists", name))
+			}
+		}
+
+		levels = append(levels, entries)
+
+		if registry.parent != nil {
+			entries = registry.parent.qualify(registry.prefix, entries)
+		}
+	}
+
+	registry := r
+
+	for _, entries := range levels {
+		maps.Copy(registry.index, entries)
+
+		registry = registry.parent
+	}
+}
+
+// removeIndex removes the given names from the index of the registry and its parents.
+func (r *Registry) removeIndex(names []string) {
+	for registry := r; registry != nil; registry = registry.parent {
+		for i, name := range names {
+			delete(registry.index, name)
+
+			if registry.parent != nil {
+				names[i] = registry.parent.namespaces[registry.prefix] + name
+			}
+		}
+	}
+}
+
+// qualify returns the entries of the child registry with the given prefix as indexed by the registry.
+func (r *Registry) qualify(prefix string, entries map[string]indexEntry) map[string]indexEntry {
+	qualified := make(map[string]indexEntry, len(entries))
+
+	for name, entry := range entries {
+		qualified[r.namespaces[prefix]+name] = indexEntry{
+			location:    entry.location.WithPrefix(prefix),
+			middlewares: slices.Concat(r.childMiddlewares[prefix], entry.middlewares),
+		}
+	}
+
+	return qualified
+}
+
+// absolute returns the location with the prefixes of the parent registries.
+func (r *Registry) absolute(location Location) Location {
+	for registry := r; registry.parent != nil; registry = registry.parent {
+		location = location.WithPrefix(registry.prefix)
+	}
+
+	return location
+}
+
+// relative returns the location of the tree relative to the registry, i.e. without the full prefix of the registry.
+func (r *Registry) relative(location Location) Location {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	prefix, _ := r.base()
+	location.prefix = strings.TrimPrefix(location.prefix, prefix)
+
+	return location
+}
+
+// base returns the full prefix and the name prefix of the registry in its tree.
+func (r *Registry) base() (string, string) {
+	prefix, namespace := "", ""
+
+	for registry := r; registry.parent != nil; registry = registry.parent {
+		prefix = registry.prefix + prefix
+		namespace = registry.parent.namespaces[registry.prefix] + namespace
+	}
+
+	return prefix, namespace
+}
+
+// sortNamespaces sorts the nested namespaces of the namespace by name.
+func sortNamespaces(namespace *Namespace) {
+	slices.SortFunc(namespace.Namespaces, func(a, b Namespace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range namespace.Namespaces {
+		sortNamespaces(&namespace.Namespaces[i])
+	}
+}
+
+// funcSuffixRegexp matches the suffix of the names of the anonymous functions.
+var funcSuffixRegexp = regexp.MustCompile(`(\.func\d+)+$`)
+
+// middlewareNames returns the names of the middlewares of the stack, from the outermost to the innermost.
+func middlewareNames(stack Stack) []string {
+	names := []string{}
+
+	for _, middleware := range slices.Backward(stack) {
+		name := "unknown"
+
+		if fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()); fn != nil {
+			name = funcSuffixRegexp.ReplaceAllString(fn.Name(), "")
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// routeOptionsMiddlewares returns the names of the middlewares of the given route options.
+func routeOptionsMiddlewares(options []RouteOption) []string {
+	route := NewRoute("", "", http.NotFoundHandler(), options...)
+
+	return middlewareNames(route.middlewares)
+}
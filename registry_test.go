@@ -1,6 +1,12 @@
 package ki
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -85,4 +91,252 @@ func TestRegistry_PanicsOnMissingKey(t *testing.T) {
 
 	reg := NewRegistry()
 	reg.Get("nonexistent") // should panic
-}
\ No newline at end of file
+}
+func TestRegistry_Attach(t *testing.T) {
+	admin := NewRegistry()
+	admin.Add("dashboard", "GET", "/dashboard")
+
+	parent := NewRegistry()
+	parent.Add("home", "GET", "/{$}")
+	parent.Attach("/admin", admin)
+
+	if got := parent.Get("dashboard").URL().Path; got != "/admin/dashboard" {
+		t.Errorf("expected path /admin/dashboard, got %s", got)
+	}
+
+	routes := parent.Routes()
+
+	expected := []RouteDescription{
+		{Name: "dashboard", Method: "GET", Pattern: "/admin/dashboard", Middlewares: []string{}},
+		{Name: "home", Method: "GET", Pattern: "/{$}", Middlewares: []string{}},
+	}
+
+	if len(routes) != len(expected) {
+		t.Fatalf("expected %d routes, got %d", len(expected), len(routes))
+	}
+
+	for i, route := range routes {
+		if route.Name != expected[i].Name || route.Method != expected[i].Method || route.Pattern != expected[i].Pattern {
+			t.Errorf("expected route %+v, got %+v", expected[i], route)
+		}
+	}
+}
+
+func TestRegistry_RelativeLookup(t *testing.T) {
+	parent := NewRegistry()
+	parent.Add("home", "GET", "/{$}")
+
+	child := parent.Child("/admin")
+	child.namespace = "admin."
+	child.Add("admin.dashboard", "GET", "/dashboard")
+
+	tests := []struct {
+		registry *Registry
+		key      string
+		path     string
+	}{
+		{registry: child, key: "dashboard", path: "/admin/dashboard"},
+		{registry: child, key: "admin.dashboard", path: "/admin/dashboard"},
+		{registry: child, key: "home", path: "/"},
+		{registry: parent, key: "admin.dashboard", path: "/admin/dashboard"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.registry.Get(tt.key).URL().Path; got != tt.path {
+			t.Errorf("expected path %s for %s, got %s", tt.path, tt.key, got)
+		}
+	}
+
+	if parent.Has("dashboard") {
+		t.Errorf("did not expect parent to resolve relative key 'dashboard'")
+	}
+}
+
+func TestRegistry_Tree(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add("home", "GET", "/{$}")
+	reg.Add("posts.index", "GET", "/posts")
+	reg.Add("posts.comments.index", "GET", "/posts/{id}/comments")
+
+	tree := reg.Tree()
+
+	if len(tree.Routes) != 1 || tree.Routes[0].Name != "home" {
+		t.Fatalf("unexpected root routes: %+v", tree.Routes)
+	}
+	if len(tree.Namespaces) != 1 || tree.Namespaces[0].Name != "posts" {
+		t.Fatalf("unexpected namespaces: %+v", tree.Namespaces)
+	}
+
+	posts := tree.Namespaces[0]
+	if len(posts.Routes) != 1 || len(posts.Namespaces) != 1 || posts.Namespaces[0].Name != "posts.comments" {
+		t.Fatalf("unexpected posts namespace: %+v", posts)
+	}
+}
+
+func TestRegistry_PanicsOnDuplicateAcrossTree(t *testing.T) {
+	tests := map[string]func(){
+		"Sibling child registries": func() {
+			parent := NewRegistry()
+			parent.Child("/v1").Add("status", "GET", "/status")
+			parent.Child("/v2").Add("status", "GET", "/status")
+		},
+		"Child and parent": func() {
+			parent := NewRegistry()
+			parent.Add("status", "GET", "/status")
+			parent.Child("/v1").Add("status", "GET", "/status")
+		},
+		"Attached registry": func() {
+			parent := NewRegistry()
+			parent.Add("status", "GET", "/status")
+
+			admin := NewRegistry()
+			admin.Add("status", "GET", "/status")
+
+			parent.Attach("/admin", admin)
+		},
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatal("expected panic on duplicate name")
+				}
+			}()
+
+			fn()
+		})
+	}
+}
+
+func TestRegistry_RoutesAreSorted(t *testing.T) {
+	parent := NewRegistry()
+
+	for _, prefix := range []string{"/c", "/a", "/b"} {
+		parent.Child(prefix).Add(prefix[1:], "GET", "/")
+	}
+
+	for range 10 {
+		routes := parent.Routes()
+
+		names := []string{}
+		for _, route := range routes {
+			names = append(names, route.Name)
+		}
+
+		if strings.Join(names, ",") != "a,b,c" {
+			t.Fatalf("expected sorted routes, got %v", names)
+		}
+	}
+}
+
+func TestRegistry_Concurrency(t *testing.T) {
+	parent := NewRegistry()
+	child := parent.Child("/v1")
+	child.Add("home", "GET", "/")
+
+	var wg sync.WaitGroup
+
+	for i := range 4 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				child.Add(fmt.Sprintf("route-%d-%d", i, j), "GET", fmt.Sprintf("/%d/%d", i, j))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				if got := parent.Get("home").URL().Path; got != "/v1/" {
+					t.Errorf("expected path /v1/, got %s", got)
+				}
+
+				_ = parent.Routes()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := len(parent.Routes()); got != 401 {
+		t.Fatalf("expected 401 routes, got %d", got)
+	}
+}
+
+func TestRegistry_Match(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add("list-posts", http.MethodGet, "/posts")
+	registry.Add("new-post", http.MethodGet, "/posts/new")
+	registry.Add("get-post", http.MethodGet, "/posts/{id}")
+	registry.Add("delete-post", http.MethodDelete, "/posts/{id}")
+	registry.Add("files", http.MethodGet, "/files/{path...}")
+	registry.Add("home", http.MethodGet, "/{$}")
+	registry.Add("blog", http.MethodGet, "blog.example.com/{$}")
+
+	admin := registry.Child("/admin")
+	admin.Add("admin.get-user", http.MethodGet, "/users/{id:[0-9]+}")
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		expected string
+		params   map[string]string
+	}{
+		{name: "Static", method: http.MethodGet, url: "/posts?page=2", expected: "list-posts", params: map[string]string{}},
+		{name: "Static over wildcard", method: http.MethodGet, url: "/posts/new", expected: "new-post", params: map[string]string{}},
+		{name: "Wildcard", method: http.MethodGet, url: "/posts/a%2Fb", expected: "get-post", params: map[string]string{"id": "a/b"}},
+		{name: "Method", method: http.MethodDelete, url: "/posts/1", expected: "delete-post", params: map[string]string{"id": "1"}},
+		{name: "Head", method: http.MethodHead, url: "/posts/1", expected: "get-post", params: map[string]string{"id": "1"}},
+		{name: "Remaining segments", method: http.MethodGet, url: "/files/a/b.txt", expected: "files", params: map[string]string{"path": "a/b.txt"}},
+		{name: "Exact", method: http.MethodGet, url: "/", expected: "home", params: map[string]string{}},
+		{name: "Host", method: http.MethodGet, url: "https://blog.example.com/", expected: "blog", params: map[string]string{}},
+		{name: "Other host", method: http.MethodGet, url: "https://www.example.com/", expected: "home", params: map[string]string{}},
+		{name: "Prefix", method: http.MethodGet, url: "/admin/users/42", expected: "admin.get-user", params: map[string]string{"id": "42"}},
+		{name: "Constraint", method: http.MethodGet, url: "/admin/users/bob"},
+		{name: "Missing prefix", method: http.MethodGet, url: "/users/42"},
+		{name: "Unknown method", method: http.MethodPost, url: "/posts"},
+		{name: "Trailing slash", method: http.MethodGet, url: "/posts/"},
+		{name: "Empty wildcard", method: http.MethodGet, url: "/posts//"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			name, params, ok := registry.Match(tt.method, u)
+
+			if ok != (tt.expected != "") {
+				t.Fatalf("Unexpected match: got=%v, want=%v", ok, tt.expected != "")
+			}
+
+			if name != tt.expected {
+				t.Fatalf("Unexpected name: got=%s, want=%s", name, tt.expected)
+			}
+
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Fatalf("Unexpected params: got=%v, want=%v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestRegistry_MatchFromChild(t *testing.T) {
+	registry := NewRegistry()
+	child := registry.Child("/api")
+	child.Add("get-post", http.MethodGet, "/posts/{id}")
+
+	name, params, ok := child.Match(http.MethodGet, &url.URL{Path: "/api/posts/1"})
+
+	if !ok || name != "get-post" || params["id"] != "1" {
+		t.Fatalf("Unexpected match: got=%s %v %v", name, params, ok)
+	}
+}
@@ -0,0 +1,96 @@
+package ki
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Indexer is a resource controller that lists the resources.
+type Indexer interface {
+	Index(w http.ResponseWriter, r *http.Request)
+}
+
+// Shower is a resource controller that shows a resource.
+type Shower interface {
+	Show(w http.ResponseWriter, r *http.Request)
+}
+
+// Creator is a resource controller that creates a resource.
+type Creator interface {
+	Create(w http.ResponseWriter, r *http.Request)
+}
+
+// Updater is a resource controller that replaces a resource.
+type Updater interface {
+	Update(w http.ResponseWriter, r *http.Request)
+}
+
+// Patcher is a resource controller that partially updates a resource.
+type Patcher interface {
+	Patch(w http.ResponseWriter, r *http.Request)
+}
+
+// Destroyer is a resource controller that deletes a resource.
+type Destroyer interface {
+	Destroy(w http.ResponseWriter, r *http.Request)
+}
+
+// Resource creates a new router with the given prefix and adds the RESTful routes implemented by the controller.
+//
+// The routes are named after the last static segment of the prefix:
+//
+//	GET    /{$}  posts.index
+//	POST   /{$}  posts.create
+//	GET    /{id} posts.show
+//	PUT    /{id} posts.update
+//	PATCH  /{id} posts.patch
+//	DELETE /{id} posts.destroy
+//
+// The names of the routes added to the returned router, including nested resources, are prefixed the same way.
+func (m *Mux) Resource(prefix string, controller any, fn func(Router)) Router {
+	mux := m.route(prefix)
+	mux.setNamePrefix(m.namePrefix + resourceName(prefix) + ".")
+
+	if c, ok := controller.(Indexer); ok {
+		mux.Get("/{$}", c.Index, WithName("index"))
+	}
+
+	if c, ok := controller.(Creator); ok {
+		mux.Post("/{$}", c.Create, WithName("create"))
+	}
+
+	if c, ok := controller.(Shower); ok {
+		mux.Get("/{id}", c.Show, WithName("show"))
+	}
+
+	if c, ok := controller.(Updater); ok {
+		mux.Put("/{id}", c.Update, WithName("update"))
+	}
+
+	if c, ok := controller.(Patcher); ok {
+		mux.Patch("/{id}", c.Patch, WithName("patch"))
+	}
+
+	if c, ok := controller.(Destroyer); ok {
+		mux.Delete("/{id}", c.Destroy, WithName("destroy"))
+	}
+
+	if fn != nil {
+		fn(mux)
+	}
+
+	return mux
+}
+
+// resourceName returns the last static segment of the prefix.
+func resourceName(prefix string) string {
+	segments := strings.Split(strings.Trim(prefix, "/"), "/")
+
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !strings.HasPrefix(segments[i], "{") {
+			return segments[i]
+		}
+	}
+
+	return "resource"
+}
@@ -0,0 +1,106 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type postController struct{}
+
+func (postController) Index(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("index"))
+}
+
+func (postController) Show(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("show " + r.PathValue("id")))
+}
+
+func (postController) Create(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("create"))
+}
+
+func (postController) Destroy(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("destroy " + r.PathValue("id")))
+}
+
+type commentController struct{}
+
+func (commentController) Index(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("comments of " + r.PathValue("postID")))
+}
+
+func (commentController) Update(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("update " + r.PathValue("postID") + " " + r.PathValue("id")))
+}
+
+func TestMux_Resource(t *testing.T) {
+	mux := NewMux()
+	mux.Resource("/posts", postController{}, func(r Router) {
+		r.Resource("/{postID}/comments", commentController{}, nil)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{method: http.MethodGet, path: "/posts/", code: http.StatusOK, body: "index"},
+		{method: http.MethodPost, path: "/posts/", code: http.StatusOK, body: "create"},
+		{method: http.MethodGet, path: "/posts/1", code: http.StatusOK, body: "show 1"},
+		{method: http.MethodDelete, path: "/posts/1", code: http.StatusOK, body: "destroy 1"},
+		{method: http.MethodPut, path: "/posts/1", code: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/posts/1/comments/", code: http.StatusOK, body: "comments of 1"},
+		{method: http.MethodPut, path: "/posts/1/comments/2", code: http.StatusOK, body: "update 1 2"},
+		{method: http.MethodGet, path: "/posts/1/comments/2", code: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("Unexpected status: got=%d, want=%d", rec.Code, tt.code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Fatalf("Unexpected body: got=%q, want=%q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestMux_ResourceNames(t *testing.T) {
+	mux := NewMux()
+	mux.Resource("/posts", postController{}, func(r Router) {
+		r.Resource("/{postID}/comments", commentController{}, nil)
+	})
+
+	expected := map[string]string{
+		"posts.index":           "/posts/",
+		"posts.create":          "/posts/",
+		"posts.show":            "/posts/1",
+		"posts.destroy":         "/posts/1",
+		"posts.comments.index":  "/posts/1/comments/",
+		"posts.comments.update": "/posts/1/comments/2",
+	}
+
+	for name, path := range expected {
+		t.Run(name, func(t *testing.T) {
+			if got := mux.Registry().Get(name).WithPathParams("1", "2").URL().String(); got != path {
+				t.Fatalf("Incorrect path: got=%s, want=%s", got, path)
+			}
+		})
+	}
+
+	missing := []string{"posts.update", "posts.patch", "posts.comments.show"}
+
+	for _, name := range missing {
+		if mux.Registry().Has(name) {
+			t.Errorf("Unexpected registry entry for %s", name)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ki
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// responseCapture is a response writer that captures the response instead of sending it.
+type responseCapture struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+// newResponseCapture returns a new responseCapture.
+func newResponseCapture() *responseCapture {
+	return &responseCapture{
+		header: http.Header{},
+	}
+}
+
+// Header returns the header of the response.
+func (c *responseCapture) Header() http.Header {
+	return c.header
+}
+
+// WriteHeader sets the status code of the response.
+func (c *responseCapture) WriteHeader(statusCode int) {
+	if c.statusCode == 0 {
+		c.statusCode = statusCode
+	}
+}
+
+// Write writes the given bytes to the body of the response.
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.statusCode == 0 {
+		c.statusCode = http.StatusOK
+	}
+
+	return c.body.Write(b)
+}
+
+// StatusCode returns the status code of the response.
+func (c *responseCapture) StatusCode() int {
+	if c.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return c.statusCode
+}
@@ -13,6 +13,7 @@ type Route struct {
 	handler     http.Handler
 	name        string
 	middlewares Stack
+	matchers    []func(*http.Request) bool
 }
 
 // RouteOption is a function that configures a Route.
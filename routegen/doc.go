@@ -0,0 +1,22 @@
+// Package routegen generates route helpers from the named routes of a ki registry.
+//
+// The generators are called from a program building the router, e.g. with go generate:
+//
+//	//go:generate go run ./internal/routes
+//
+//	func main() {
+//		router := app.NewRouter()
+//		_ = routegen.TypeScript(os.Stdout, router.Registry().Routes())
+//		_ = routegen.Go(f, router.Registry().Routes(), routegen.GoOptions{Package: "routes"})
+//	}
+//
+// The Go builders take one string type per path parameter, e.g. PostIDParam for "postID", instead of plain strings.
+// When the parameters of a pattern are reordered, the calls with the previous order no longer compile instead of
+// building wrong URLs. The values are converted at the call site:
+//
+//	routes.GetPostComment(routes.PostIDParam(postID), routes.CommentIDParam(commentID))
+//
+// The routes can also be exported as JSON and given to the routegen command:
+//
+//	go run github.com/throskam/ki/cmd/routegen -in routes.json -out routes.ts
+package routegen
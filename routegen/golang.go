@@ -0,0 +1,188 @@
+package routegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/throskam/ki"
+)
+
+// GoOptions are the options of the Go generator.
+type GoOptions struct {
+	// Package is the name of the generated package.
+	Package string
+	// Queries are the query parameters of the routes by route name.
+	// A route with query parameters gets a query struct with one string field per parameter.
+	Queries map[string][]string
+}
+
+// Go writes a Go file with one typed builder per named route.
+// Every path parameter gets its own string type, so that a builder called with its parameters in the wrong order does
+// not compile. The builder of the route "get-post-comment" with the pattern "/posts/{postID}/comments/{commentID}" is
+//
+//	func GetPostComment(postID PostIDParam, commentID CommentIDParam) ki.Location
+//
+// and the builder of the route "list-posts" with the query parameters "page" and "sort" is
+//
+//	func ListPosts(q ListPostsQuery) ki.Location
+//
+// where ListPostsQuery has the fields Page and Sort, the empty fields being omitted from the query.
+func Go(w io.Writer, routes []ki.RouteDescription, options GoOptions) error {
+	if !token.IsIdentifier(options.Package) {
+		return fmt.Errorf("invalid package name %q", options.Package)
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by routegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", options.Package)
+
+	// An unused import would not compile.
+	if len(routes) > 0 {
+		b.WriteString("import \"github.com/throskam/ki\"\n")
+	}
+
+	seen := map[string]string{}
+
+	declare := func(identifier, name string) error {
+		if other, ok := seen[identifier]; ok {
+			return fmt.Errorf("routes %q and %q have the same identifier %s", other, name, identifier)
+		}
+
+		seen[identifier] = name
+
+		return nil
+	}
+
+	// The path parameters of the same name share their type across the routes.
+	types := map[string]string{}
+
+	for _, route := range routes {
+		for _, param := range params(parsePattern(route.Pattern)) {
+			if _, ok := types[param]; ok {
+				continue
+			}
+
+			typ, err := camelCase(param, true)
+			if err != nil || !token.IsIdentifier(typ+"Param") {
+				return fmt.Errorf("route %q: invalid path parameter %q", route.Name, param)
+			}
+
+			if err := declare(typ+"Param", route.Name); err != nil {
+				return err
+			}
+
+			types[param] = typ + "Param"
+		}
+	}
+
+	for _, param := range slices.Sorted(maps.Keys(types)) {
+		fmt.Fprintf(&b, "\n// %s is the path parameter %s.\n", types[param], strconv.Quote(param))
+		fmt.Fprintf(&b, "type %s string\n", types[param])
+	}
+
+	for _, route := range routes {
+		name, err := camelCase(route.Name, true)
+		if err != nil {
+			return err
+		}
+
+		if unicode.IsDigit(rune(name[0])) {
+			name = "Route" + name
+		}
+
+		if err := declare(name, route.Name); err != nil {
+			return err
+		}
+
+		args := []string{}
+		pathParams := []string{}
+
+		for _, param := range params(parsePattern(route.Pattern)) {
+			arg, err := goParam(param)
+			if err != nil {
+				return fmt.Errorf("route %q: %w", route.Name, err)
+			}
+
+			args = append(args, arg+" "+types[param])
+			pathParams = append(pathParams, fmt.Sprintf("%s: string(%s)", strconv.Quote(param), arg))
+		}
+
+		query := options.Queries[route.Name]
+
+		if len(query) > 0 {
+			if err := declare(name+"Query", route.Name); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(&b, "\n// %sQuery is the query of the route %s.\n", name, strconv.Quote(route.Name))
+			fmt.Fprintf(&b, "type %sQuery struct {\n", name)
+
+			for _, key := range query {
+				field, err := camelCase(key, true)
+				if err != nil || !token.IsIdentifier(field) {
+					return fmt.Errorf("route %q: invalid query parameter %q", route.Name, key)
+				}
+
+				fmt.Fprintf(&b, "%s string\n", field)
+			}
+
+			b.WriteString("}\n")
+
+			args = append(args, fmt.Sprintf("q %sQuery", name))
+		}
+
+		fmt.Fprintf(&b, "\n// %s returns the location of the route %s: %s %s.\n", name, strconv.Quote(route.Name), route.Method, route.Pattern)
+		fmt.Fprintf(&b, "func %s(%s) ki.Location {\n", name, strings.Join(args, ", "))
+		fmt.Fprintf(&b, "l := ki.NewLocation(%s, %s)", strconv.Quote(route.Method), strconv.Quote(route.Pattern))
+
+		if len(pathParams) > 0 {
+			fmt.Fprintf(&b, ".WithParams(map[string]string{%s})", strings.Join(pathParams, ", "))
+		}
+
+		b.WriteString("\n")
+
+		for _, key := range query {
+			field, _ := camelCase(key, true)
+
+			fmt.Fprintf(&b, "\nif q.%s != \"\" {\nl = l.WithQueryParam(%s, q.%s)\n}\n", field, strconv.Quote(key), field)
+		}
+
+		b.WriteString("\nreturn l\n}\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// goParam returns the argument name of the path parameter.
+func goParam(param string) (string, error) {
+	arg, err := camelCase(param, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid path parameter %q", param)
+	}
+
+	if token.IsKeyword(arg) || arg == "q" || arg == "l" || arg == "ki" {
+		arg += "Param"
+	}
+
+	if !token.IsIdentifier(arg) {
+		return "", fmt.Errorf("invalid path parameter %q", param)
+	}
+
+	return arg, nil
+}
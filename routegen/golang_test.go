@@ -0,0 +1,170 @@
+package routegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/throskam/ki"
+)
+
+func TestGo(t *testing.T) {
+	routes := []ki.RouteDescription{
+		{Name: "get-post-comment", Method: http.MethodGet, Pattern: "/posts/{postID}/comments/{commentID}"},
+		{Name: "list-posts", Method: http.MethodGet, Pattern: "/posts"},
+		{Name: "files", Method: http.MethodGet, Pattern: "/files/{type}/{path...}"},
+	}
+
+	var b strings.Builder
+
+	err := Go(&b, routes, GoOptions{
+		Package: "routes",
+		Queries: map[string][]string{"list-posts": {"page", "sort"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	expected := `// Code generated by routegen. DO NOT EDIT.
+
+package routes
+
+import "github.com/throskam/ki"
+
+// CommentIDParam is the path parameter "commentID".
+type CommentIDParam string
+
+// PathParam is the path parameter "path".
+type PathParam string
+
+// PostIDParam is the path parameter "postID".
+type PostIDParam string
+
+// TypeParam is the path parameter "type".
+type TypeParam string
+
+// GetPostComment returns the location of the route "get-post-comment": GET /posts/{postID}/comments/{commentID}.
+func GetPostComment(postID PostIDParam, commentID CommentIDParam) ki.Location {
+	l := ki.NewLocation("GET", "/posts/{postID}/comments/{commentID}").WithParams(map[string]string{"postID": string(postID), "commentID": string(commentID)})
+
+	return l
+}
+
+// ListPostsQuery is the query of the route "list-posts".
+type ListPostsQuery struct {
+	Page string
+	Sort string
+}
+
+// ListPosts returns the location of the route "list-posts": GET /posts.
+func ListPosts(q ListPostsQuery) ki.Location {
+	l := ki.NewLocation("GET", "/posts")
+
+	if q.Page != "" {
+		l = l.WithQueryParam("page", q.Page)
+	}
+
+	if q.Sort != "" {
+		l = l.WithQueryParam("sort", q.Sort)
+	}
+
+	return l
+}
+
+// Files returns the location of the route "files": GET /files/{type}/{path...}.
+func Files(typeParam TypeParam, path PathParam) ki.Location {
+	l := ki.NewLocation("GET", "/files/{type}/{path...}").WithParams(map[string]string{"type": string(typeParam), "path": string(path)})
+
+	return l
+}
+`
+
+	if b.String() != expected {
+		t.Fatalf("Unexpected file: got=%s, want=%s", b.String(), expected)
+	}
+}
+
+func TestGo_ParamOrder(t *testing.T) {
+	generate := func(pattern string) string {
+		var b strings.Builder
+
+		routes := []ki.RouteDescription{{Name: "get-post-comment", Method: http.MethodGet, Pattern: pattern}}
+
+		if err := Go(&b, routes, GoOptions{Package: "routes"}); err != nil {
+			t.Fatalf("Unexpected error: got=%v", err)
+		}
+
+		return b.String()
+	}
+
+	tests := map[string]string{
+		"/posts/{postID}/comments/{commentID}": "func GetPostComment(postID PostIDParam, commentID CommentIDParam) ki.Location",
+		"/comments/{commentID}/posts/{postID}": "func GetPostComment(commentID CommentIDParam, postID PostIDParam) ki.Location",
+	}
+
+	for pattern, signature := range tests {
+		if got := generate(pattern); !strings.Contains(got, signature) {
+			t.Fatalf("Unexpected signature for %s: got=%s, want=%s", pattern, got, signature)
+		}
+	}
+}
+
+func TestGo_NoRoutes(t *testing.T) {
+	var b strings.Builder
+
+	if err := Go(&b, nil, GoOptions{Package: "routes"}); err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "routes.go", b.String(), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	if _, err := (&types.Config{}).Check("routes", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+}
+
+func TestGo_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []ki.RouteDescription
+		options GoOptions
+	}{
+		{
+			name:    "Invalid package",
+			options: GoOptions{Package: "my-routes"},
+		},
+		{
+			name: "Duplicate identifier",
+			routes: []ki.RouteDescription{
+				{Name: "get-post", Method: http.MethodGet, Pattern: "/posts/{id}"},
+				{Name: "get.post", Method: http.MethodGet, Pattern: "/post/{id}"},
+			},
+			options: GoOptions{Package: "routes"},
+		},
+		{
+			name: "Query type conflict",
+			routes: []ki.RouteDescription{
+				{Name: "list-posts", Method: http.MethodGet, Pattern: "/posts"},
+				{Name: "list-posts-query", Method: http.MethodGet, Pattern: "/posts/query"},
+			},
+			options: GoOptions{Package: "routes", Queries: map[string][]string{"list-posts": {"page"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Go(&strings.Builder{}, tt.routes, tt.options); err == nil {
+				t.Fatalf("Unexpected error: got=nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package routegen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// placeholderRegexp matches the placeholders of a pattern.
+var placeholderRegexp = regexp.MustCompile(`\{[^}]*\}`)
+
+// segment is a part of a pattern, either a literal or a parameter.
+type segment struct {
+	literal  string
+	param    string
+	wildcard bool
+}
+
+// parsePattern splits the pattern into its literals and parameters.
+// The "{$}" placeholder is dropped and the constraints of the parameters are stripped.
+func parsePattern(pattern string) []segment {
+	segments := []segment{}
+	last := 0
+
+	for _, loc := range placeholderRegexp.FindAllStringIndex(pattern, -1) {
+		if loc[0] > last {
+			segments = append(segments, segment{literal: pattern[last:loc[0]]})
+		}
+
+		last = loc[1]
+
+		placeholder := pattern[loc[0]+1 : loc[1]-1]
+		if placeholder == "$" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(placeholder, ":")
+
+		segments = append(segments, segment{
+			param:    strings.TrimSuffix(name, "..."),
+			wildcard: strings.HasSuffix(name, "..."),
+		})
+	}
+
+	if last < len(pattern) {
+		segments = append(segments, segment{literal: pattern[last:]})
+	}
+
+	return segments
+}
+
+// params returns the parameters of the segments.
+func params(segments []segment) []string {
+	names := []string{}
+
+	for _, s := range segments {
+		if s.param != "" {
+			names = append(names, s.param)
+		}
+	}
+
+	return names
+}
+
+// words splits the route name into its alphanumeric words, e.g. "admin.get-post" is "admin", "get" and "post".
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// camelCase returns the words joined in camel case, the first word being lower-cased unless exported is true.
+// The identifier may start with a digit.
+func camelCase(name string, exported bool) (string, error) {
+	var b strings.Builder
+
+	for i, word := range words(name) {
+		runes := []rune(word)
+
+		if i > 0 || exported {
+			runes[0] = unicode.ToUpper(runes[0])
+		} else {
+			runes[0] = unicode.ToLower(runes[0])
+		}
+
+		b.WriteString(string(runes))
+	}
+
+	if b.Len() == 0 {
+		return "", fmt.Errorf("route %q has no identifier", name)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,60 @@
+package routegen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := map[string][]segment{
+		"/posts":     {{literal: "/posts"}},
+		"/posts/{$}": {{literal: "/posts/"}},
+		"/posts/{id:[0-9]+}/comments": {
+			{literal: "/posts/"},
+			{param: "id"},
+			{literal: "/comments"},
+		},
+		"/files/{path...}": {
+			{literal: "/files/"},
+			{param: "path", wildcard: true},
+		},
+	}
+
+	for pattern, expected := range tests {
+		t.Run(pattern, func(t *testing.T) {
+			if got := parsePattern(pattern); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("Unexpected segments: got=%v, want=%v", got, expected)
+			}
+		})
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		exported bool
+		expected string
+	}{
+		{name: "get-post", expected: "getPost"},
+		{name: "admin.users.show", expected: "adminUsersShow"},
+		{name: "v2.posts_index", exported: true, expected: "V2PostsIndex"},
+		{name: "2fa", expected: "2fa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := camelCase(tt.name, tt.exported)
+			if err != nil {
+				t.Fatalf("Unexpected error: got=%v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("Unexpected identifier: got=%q, want=%q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := camelCase("--", false); err == nil {
+		t.Fatalf("Unexpected error: got=nil")
+	}
+}
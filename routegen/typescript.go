@@ -0,0 +1,135 @@
+package routegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/throskam/ki"
+)
+
+// typeScriptReserved are the reserved words of TypeScript which cannot name a function.
+var typeScriptReserved = []string{
+	"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum",
+	"export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null",
+	"return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with",
+}
+
+// typeScriptHeader is the beginning of the generated TypeScript module.
+const typeScriptHeader = `// Code generated by routegen. DO NOT EDIT.
+
+export type QueryValue = string | number | boolean;
+
+export type Query = Record<string, QueryValue | QueryValue[] | undefined>;
+
+export interface Route {
+  method: string;
+  url: string;
+}
+
+function query(q?: Query): string {
+  if (!q) {
+    return "";
+  }
+
+  const params = new URLSearchParams();
+
+  for (const key of Object.keys(q).sort()) {
+    const value = q[key];
+
+    if (value === undefined) {
+      continue;
+    }
+
+    for (const v of Array.isArray(value) ? value : [value]) {
+      params.append(key, String(v));
+    }
+  }
+
+  const s = params.toString();
+
+  return s ? "?" + s : "";
+}
+
+function param(value: QueryValue): string {
+  return encodeURIComponent(String(value));
+}
+
+function wildcard(value: QueryValue): string {
+  return String(value).split("/").map(encodeURIComponent).join("/");
+}
+`
+
+// TypeScript writes a TypeScript module with one function per named route.
+// The function of the route "get-post" with the pattern "/posts/{postID}" is
+//
+//	getPost(params: { postID: string | number }, q?: Query): Route
+//
+// which returns the method and the URL of the route, the path parameters and the query being escaped.
+func TypeScript(w io.Writer, routes []ki.RouteDescription) error {
+	var b bytes.Buffer
+
+	b.WriteString(typeScriptHeader)
+
+	seen := map[string]string{}
+
+	for _, route := range routes {
+		name, err := camelCase(route.Name, false)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(typeScriptReserved, name) {
+			name += "Route"
+		}
+
+		if unicode.IsDigit(rune(name[0])) {
+			name = "_" + name
+		}
+
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("routes %q and %q have the same function name %s", other, route.Name, name)
+		}
+
+		seen[name] = route.Name
+
+		segments := parsePattern(route.Pattern)
+
+		var url strings.Builder
+
+		for _, s := range segments {
+			switch {
+			case s.param == "":
+				url.WriteString(strings.NewReplacer("`", "\\`", "$", "\\$", "\\", "\\\\").Replace(s.literal))
+			case s.wildcard:
+				fmt.Fprintf(&url, "${wildcard(params[%s])}", strconv.Quote(s.param))
+			default:
+				fmt.Fprintf(&url, "${param(params[%s])}", strconv.Quote(s.param))
+			}
+		}
+
+		args := "q?: Query"
+
+		if names := params(segments); len(names) > 0 {
+			fields := make([]string, len(names))
+			for i, n := range names {
+				fields[i] = fmt.Sprintf("%s: QueryValue", strconv.Quote(n))
+			}
+
+			args = fmt.Sprintf("params: { %s }, %s", strings.Join(fields, "; "), args)
+		}
+
+		fmt.Fprintf(&b, "\n/** %s %s */\n", route.Method, route.Pattern)
+		fmt.Fprintf(&b, "export function %s(%s): Route {\n", name, args)
+		fmt.Fprintf(&b, "  return { method: %s, url: `%s` + query(q) };\n", strconv.Quote(route.Method), url.String())
+		b.WriteString("}\n")
+	}
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
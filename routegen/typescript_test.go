@@ -0,0 +1,60 @@
+package routegen
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/throskam/ki"
+)
+
+func TestTypeScript(t *testing.T) {
+	router := ki.NewRouter()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	router.Get("/posts", noop, ki.WithName("list-posts"))
+	router.Route("/posts/{postID}", func(r ki.Router) {
+		r.Get("/{$}", noop, ki.WithName("get-post"))
+		r.Delete("/{$}", noop, ki.WithName("delete"))
+	})
+	router.Get("/files/{path...}", noop, ki.WithName("files"))
+
+	var b strings.Builder
+
+	if err := TypeScript(&b, router.Registry().Routes()); err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	expected := []string{
+		"/** DELETE /posts/{postID}/{$} */\n" +
+			"export function deleteRoute(params: { \"postID\": QueryValue }, q?: Query): Route {\n" +
+			"  return { method: \"DELETE\", url: `/posts/${param(params[\"postID\"])}/` + query(q) };\n" +
+			"}\n",
+		"/** GET /files/{path...} */\n" +
+			"export function files(params: { \"path\": QueryValue }, q?: Query): Route {\n" +
+			"  return { method: \"GET\", url: `/files/${wildcard(params[\"path\"])}` + query(q) };\n" +
+			"}\n",
+		"/** GET /posts */\n" +
+			"export function listPosts(q?: Query): Route {\n" +
+			"  return { method: \"GET\", url: `/posts` + query(q) };\n" +
+			"}\n",
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(b.String(), e) {
+			t.Fatalf("Unexpected module: got=%s, want=%s", b.String(), e)
+		}
+	}
+}
+
+func TestTypeScript_DuplicateName(t *testing.T) {
+	routes := []ki.RouteDescription{
+		{Name: "get-post", Method: http.MethodGet, Pattern: "/posts/{id}"},
+		{Name: "get.post", Method: http.MethodGet, Pattern: "/post/{id}"},
+	}
+
+	if err := TypeScript(&strings.Builder{}, routes); err == nil {
+		t.Fatalf("Unexpected error: got=nil")
+	}
+}
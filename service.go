@@ -0,0 +1,190 @@
+package ki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+// RPCCode is the code of an RPC error.
+type RPCCode string
+
+// RPC error codes.
+const (
+	CodeCanceled           RPCCode = "canceled"
+	CodeUnknown            RPCCode = "unknown"
+	CodeInvalidArgument    RPCCode = "invalid_argument"
+	CodeMalformed          RPCCode = "malformed"
+	CodeDeadlineExceeded   RPCCode = "deadline_exceeded"
+	CodeNotFound           RPCCode = "not_found"
+	CodeBadRoute           RPCCode = "bad_route"
+	CodeAlreadyExists      RPCCode = "already_exists"
+	CodePermissionDenied   RPCCode = "permission_denied"
+	CodeUnauthenticated    RPCCode = "unauthenticated"
+	CodeResourceExhausted  RPCCode = "resource_exhausted"
+	CodeFailedPrecondition RPCCode = "failed_precondition"
+	CodeAborted            RPCCode = "aborted"
+	CodeOutOfRange         RPCCode = "out_of_range"
+	CodeUnimplemented      RPCCode = "unimplemented"
+	CodeInternal           RPCCode = "internal"
+	CodeUnavailable        RPCCode = "unavailable"
+	CodeDataLoss           RPCCode = "data_loss"
+)
+
+// rpcStatuses maps the RPC error codes to the HTTP statuses.
+var rpcStatuses = map[RPCCode]int{
+	CodeCanceled:           http.StatusRequestTimeout,
+	CodeUnknown:            http.StatusInternalServerError,
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeMalformed:          http.StatusBadRequest,
+	CodeDeadlineExceeded:   http.StatusRequestTimeout,
+	CodeNotFound:           http.StatusNotFound,
+	CodeBadRoute:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeFailedPrecondition: http.StatusPreconditionFailed,
+	CodeAborted:            http.StatusConflict,
+	CodeOutOfRange:         http.StatusBadRequest,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeDataLoss:           http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status of the code.
+func (c RPCCode) Status() int {
+	status, ok := rpcStatuses[c]
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	return status
+}
+
+// RPCError is an error returned by an RPC method.
+type RPCError struct {
+	Code    RPCCode `json:"code"`
+	Message string  `json:"msg"`
+}
+
+// NewRPCError returns a new RPCError.
+func NewRPCError(code RPCCode, message string) *RPCError {
+	return &RPCError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error implements the error interface.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// Service creates a new router with the given prefix exposing the exported methods of the service.
+//
+// Every method with the signature func(context.Context, *Request) (*Response, error) is exposed as
+// POST /{Service}/{Method}, with JSON request and response bodies, and named "{Service}.{Method}".
+// The errors are returned as JSON RPCError with the HTTP status of their code.
+// It panics if the service has no such method.
+func (m *Mux) Service(prefix string, service any, options ...RouteOption) Router {
+	value := reflect.ValueOf(service)
+	name := reflect.Indirect(value).Type().Name()
+
+	mux := m.route(prefix)
+
+	count := 0
+
+	for i := range value.NumMethod() {
+		method := value.Type().Method(i)
+
+		if !isRPCMethod(method.Type) {
+			continue
+		}
+
+		mux.Post(fmt.Sprintf("/%s/%s", name, method.Name), rpcHandler(value.Method(i)),
+			append([]RouteOption{WithName(name + "." + method.Name)}, options...)...)
+
+		count++
+	}
+
+	if count == 0 {
+		panic(fmt.Sprintf("Service %s has no RPC method", name))
+	}
+
+	return mux
+}
+
+// isRPCMethod returns true if the method has the signature func(context.Context, *Request) (*Response, error).
+func isRPCMethod(t reflect.Type) bool {
+	return t.NumIn() == 3 &&
+		t.In(1) == contextType &&
+		t.In(2).Kind() == reflect.Pointer &&
+		t.NumOut() == 2 &&
+		t.Out(0).Kind() == reflect.Pointer &&
+		t.Out(1) == errorType
+}
+
+// rpcHandler returns the handler calling the RPC method.
+func rpcHandler(method reflect.Value) http.HandlerFunc {
+	requestType := method.Type().In(1).Elem()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if contentType := r.Header.Get("Content-Type"); contentType != "" {
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil || mediaType != "application/json" {
+				writeRPCError(w, NewRPCError(CodeMalformed, "unsupported content type"))
+				return
+			}
+		}
+
+		request := reflect.New(requestType)
+
+		err := json.NewDecoder(r.Body).Decode(request.Interface())
+		if err != nil && !errors.Is(err, io.EOF) {
+			writeRPCError(w, NewRPCError(CodeMalformed, "cannot decode request"))
+			return
+		}
+
+		out := method.Call([]reflect.Value{reflect.ValueOf(r.Context()), request})
+
+		if err, _ := out[1].Interface().(error); err != nil {
+			writeRPCError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out[0].Interface())
+	}
+}
+
+// writeRPCError writes the error as a JSON RPCError.
+func writeRPCError(w http.ResponseWriter, err error) {
+	var rpcErr *RPCError
+
+	switch {
+	case errors.As(err, &rpcErr):
+	case errors.Is(err, context.Canceled):
+		rpcErr = NewRPCError(CodeCanceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		rpcErr = NewRPCError(CodeDeadlineExceeded, err.Error())
+	default:
+		rpcErr = NewRPCError(CodeInternal, http.StatusText(http.StatusInternalServerError))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rpcErr.Code.Status())
+
+	_ = json.NewEncoder(w).Encode(rpcErr)
+}
@@ -0,0 +1,180 @@
+package ki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type GetPostRequest struct {
+	ID string `json:"id"`
+}
+
+type GetPostResponse struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type PostService struct{}
+
+func (s *PostService) GetPost(ctx context.Context, req *GetPostRequest) (*GetPostResponse, error) {
+	switch req.ID {
+	case "":
+		return nil, NewRPCError(CodeInvalidArgument, "id is required")
+	case "missing":
+		return nil, NewRPCError(CodeNotFound, "post not found")
+	case "boom":
+		return nil, errors.New("database is down")
+	}
+
+	return &GetPostResponse{ID: req.ID, Title: "Hello " + ctx.Value(contextKey("user")).(string)}, nil
+}
+
+func (s *PostService) Helper(id string) string {
+	return id
+}
+
+func TestMux_Service(t *testing.T) {
+	mux := NewMux()
+	mux.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), contextKey("user"), "alice")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	mux.Service("/rpc", &PostService{})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		code        int
+		expected    string
+	}{
+		{
+			name:     "Success",
+			method:   http.MethodPost,
+			path:     "/rpc/PostService/GetPost",
+			body:     `{"id":"1"}`,
+			code:     http.StatusOK,
+			expected: `{"id":"1","title":"Hello alice"}`,
+		},
+		{
+			name:     "Invalid argument",
+			method:   http.MethodPost,
+			path:     "/rpc/PostService/GetPost",
+			code:     http.StatusBadRequest,
+			expected: `{"code":"invalid_argument","msg":"id is required"}`,
+		},
+		{
+			name:     "Not found",
+			method:   http.MethodPost,
+			path:     "/rpc/PostService/GetPost",
+			body:     `{"id":"missing"}`,
+			code:     http.StatusNotFound,
+			expected: `{"code":"not_found","msg":"post not found"}`,
+		},
+		{
+			name:     "Internal error",
+			method:   http.MethodPost,
+			path:     "/rpc/PostService/GetPost",
+			body:     `{"id":"boom"}`,
+			code:     http.StatusInternalServerError,
+			expected: `{"code":"internal","msg":"Internal Server Error"}`,
+		},
+		{
+			name:     "Malformed body",
+			method:   http.MethodPost,
+			path:     "/rpc/PostService/GetPost",
+			body:     `{"id":`,
+			code:     http.StatusBadRequest,
+			expected: `{"code":"malformed","msg":"cannot decode request"}`,
+		},
+		{
+			name:        "Unsupported content type",
+			method:      http.MethodPost,
+			path:        "/rpc/PostService/GetPost",
+			contentType: "text/plain",
+			body:        `{"id":"1"}`,
+			code:        http.StatusBadRequest,
+			expected:    `{"code":"malformed","msg":"unsupported content type"}`,
+		},
+		{
+			name:   "Wrong method",
+			method: http.MethodGet,
+			path:   "/rpc/PostService/GetPost",
+			code:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "Unexposed method",
+			method: http.MethodPost,
+			path:   "/rpc/PostService/Helper",
+			code:   http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("Unexpected status for %s: got=%d, want=%d", tt.path, rec.Code, tt.code)
+			}
+			if tt.expected != "" && strings.TrimSpace(rec.Body.String()) != tt.expected {
+				t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestMux_Service_Name(t *testing.T) {
+	mux := NewMux()
+	mux.Service("/rpc", &PostService{})
+
+	location := mux.Registry().Get("PostService.GetPost")
+
+	if location.Method() != http.MethodPost || location.URL().String() != "/rpc/PostService/GetPost" {
+		t.Fatalf("Unexpected location: got=%s %s", location.Method(), location.URL())
+	}
+}
+
+func TestMux_Service_NoMethod(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for service without RPC method")
+		}
+	}()
+
+	NewMux().Service("/rpc", struct{}{})
+}
+
+func TestRPCError(t *testing.T) {
+	err := error(NewRPCError(CodeAlreadyExists, "post exists"))
+
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code.Status() != http.StatusConflict {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	body, _ := json.Marshal(rpcErr)
+	if string(body) != `{"code":"already_exists","msg":"post exists"}` {
+		t.Fatalf("Unexpected body: got=%s", body)
+	}
+
+	if got := RPCCode("unknown_code").Status(); got != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", got)
+	}
+}
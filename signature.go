@@ -0,0 +1,112 @@
+package ki
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureParam is the query parameter of the signature of a signed URL.
+	SignatureParam = "signature"
+	// ExpiresParam is the query parameter of the expiry, in Unix seconds, of a signed URL.
+	ExpiresParam = "expires"
+	// UnsignedParam is the query parameter listing the query parameters excluded from the signature of a signed URL.
+	UnsignedParam = "unsigned"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of a URL is missing or invalid.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrExpiredSignature is returned when the signature of a URL is expired.
+	ErrExpiredSignature = errors.New("expired signature")
+)
+
+// Sign returns the parameterized URL signed with the key and expiring at the given time.
+// The signature covers the path and the query except the excluded query parameters, which can then be added or
+// changed without invalidating the URL, e.g. tracking parameters.
+// It panics if the URL is invalid.
+func (l Location) Sign(key []byte, expiresAt time.Time, exclude ...string) *url.URL {
+	u := l.URL()
+
+	query := u.Query()
+	query.Set(ExpiresParam, strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Del(SignatureParam)
+	query.Del(UnsignedParam)
+
+	if len(exclude) > 0 {
+		exclude = slices.Sorted(slices.Values(exclude))
+		query.Set(UnsignedParam, strings.Join(exclude, ","))
+	}
+
+	query.Set(SignatureParam, signature(key, u.EscapedPath(), query))
+
+	u.RawQuery = query.Encode()
+
+	return u
+}
+
+// VerifySignature checks that the URL is signed by one of the keys and not expired.
+// Several keys allow a key rotation, the URLs signed with the previous keys remaining valid.
+func VerifySignature(u *url.URL, keys ...[]byte) error {
+	query := u.Query()
+
+	expected, err := base64.RawURLEncoding.DecodeString(query.Get(SignatureParam))
+	if err != nil || len(expected) == 0 {
+		return ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	valid := false
+
+	for _, key := range keys {
+		got, _ := base64.RawURLEncoding.DecodeString(signature(key, u.EscapedPath(), query))
+
+		if hmac.Equal(got, expected) {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() >= expires {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+// signature returns the signature of the path and the query with the key.
+// The signature parameter and the parameters listed by the unsigned parameter are not signed.
+func signature(key []byte, path string, query url.Values) string {
+	signed := url.Values{}
+
+	maps.Copy(signed, query)
+
+	delete(signed, SignatureParam)
+
+	for name := range strings.SplitSeq(query.Get(UnsignedParam), ",") {
+		if name != UnsignedParam && name != ExpiresParam {
+			delete(signed, name)
+		}
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path + "?" + signed.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,103 @@
+package ki
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLocation_Sign(t *testing.T) {
+	key := []byte("secret")
+	location := NewLocation(http.MethodGet, "/downloads/{id}").WithPathParams("42").WithQueryParam("format", "pdf")
+
+	u := location.Sign(key, time.Now().Add(time.Hour), "utm_source")
+
+	if u.Path != "/downloads/42" {
+		t.Fatalf("Unexpected path: got=%s, want=%s", u.Path, "/downloads/42")
+	}
+
+	for _, param := range []string{SignatureParam, ExpiresParam, UnsignedParam, "format"} {
+		if !u.Query().Has(param) {
+			t.Fatalf("Unexpected query: got=%s, want=%s", u.RawQuery, param)
+		}
+	}
+
+	tamper := func(fn func(q url.Values)) *url.URL {
+		clone := *u
+		q := clone.Query()
+		fn(q)
+		clone.RawQuery = q.Encode()
+
+		return &clone
+	}
+
+	tests := []struct {
+		name     string
+		url      *url.URL
+		keys     [][]byte
+		expected error
+	}{
+		{name: "Valid", url: u, keys: [][]byte{key}},
+		{name: "Rotated key", url: u, keys: [][]byte{[]byte("new"), key}},
+		{name: "Wrong key", url: u, keys: [][]byte{[]byte("other")}, expected: ErrInvalidSignature},
+		{name: "No key", url: u, expected: ErrInvalidSignature},
+		{
+			name:     "Excluded param",
+			url:      tamper(func(q url.Values) { q.Set("utm_source", "newsletter") }),
+			keys:     [][]byte{key},
+			expected: nil,
+		},
+		{
+			name:     "Tampered param",
+			url:      tamper(func(q url.Values) { q.Set("format", "zip") }),
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "Added param",
+			url:      tamper(func(q url.Values) { q.Set("admin", "1") }),
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "Tampered expiry",
+			url:      tamper(func(q url.Values) { q.Set(ExpiresParam, "99999999999") }),
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "Tampered exclusion",
+			url:      tamper(func(q url.Values) { q.Set(UnsignedParam, "format,utm_source") }),
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "Missing signature",
+			url:      tamper(func(q url.Values) { q.Del(SignatureParam) }),
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "Expired",
+			url:      location.Sign(key, time.Now().Add(-time.Second)),
+			keys:     [][]byte{key},
+			expected: ErrExpiredSignature,
+		},
+		{
+			name:     "Other path",
+			url:      &url.URL{Path: "/downloads/43", RawQuery: u.RawQuery},
+			keys:     [][]byte{key},
+			expected: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignature(tt.url, tt.keys...); !errors.Is(err, tt.expected) {
+				t.Fatalf("Unexpected error: got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
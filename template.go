@@ -0,0 +1,99 @@
+package ki
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+)
+
+// TemplateFuncs returns the template functions resolving the named routes of the registry:
+//
+//	{{ url "get-post" .ID }}            the URL of the route with the given path parameters
+//	{{ urlq "list-posts" "page" 2 }}    the URL of the route with the path parameters followed by query pairs
+//	{{ method "delete-post" }}          the method of the route
+//
+// The functions return an error, which stops the execution of the template, if the route does not exist or the
+// parameters do not match the pattern of the route.
+func TemplateFuncs(registry *Registry) template.FuncMap {
+	return template.FuncMap{
+		"url": func(name string, params ...any) (string, error) {
+			return templateURL(registry, name, params, false)
+		},
+		"urlq": func(name string, params ...any) (string, error) {
+			return templateURL(registry, name, params, true)
+		},
+		"method": func(name string) (string, error) {
+			location, err := templateLocation(registry, name)
+			if err != nil {
+				return "", err
+			}
+
+			return location.Method(), nil
+		},
+	}
+}
+
+// TemplateFuncsFromContext returns the template functions resolving the named routes of the registry in the context.
+// Use with Locator middleware.
+func TemplateFuncsFromContext(ctx context.Context) template.FuncMap {
+	registry, _ := ctx.Value(registryContextKey).(*Registry)
+
+	return TemplateFuncs(registry)
+}
+
+// templateLocation returns the location of the named route.
+func templateLocation(registry *Registry, name string) (Location, error) {
+	if registry == nil {
+		return Location{}, errors.New("no registry")
+	}
+
+	location, ok := registry.find(name)
+	if !ok {
+		return Location{}, fmt.Errorf("route %q does not exist", name)
+	}
+
+	return location, nil
+}
+
+// templateURL returns the URL of the named route with the given path parameters, followed by query pairs if query
+// is true.
+func templateURL(registry *Registry, name string, params []any, query bool) (string, error) {
+	location, err := templateLocation(registry, name)
+	if err != nil {
+		return "", err
+	}
+
+	count := len(location.placeholders())
+
+	if len(params) < count {
+		return "", fmt.Errorf("route %q expects %d path parameters, got %d", name, count, len(params))
+	}
+
+	if !query && len(params) > count {
+		return "", fmt.Errorf("route %q expects %d path parameters, got %d", name, count, len(params))
+	}
+
+	pairs := params[count:]
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("route %q expects query pairs, got %d values", name, len(pairs))
+	}
+
+	pathParams := make([]string, count)
+	for i, param := range params[:count] {
+		pathParams[i] = fmt.Sprint(param)
+	}
+
+	location = location.WithPathParams(pathParams...)
+
+	for i := 0; i < len(pairs); i += 2 {
+		location = location.WithQueryParam(fmt.Sprint(pairs[i]), fmt.Sprint(pairs[i+1]))
+	}
+
+	u, err := location.URLE()
+	if err != nil {
+		return "", fmt.Errorf("route %q: %w", name, err)
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,88 @@
+package ki
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add("list-posts", http.MethodGet, "/posts")
+	registry.Add("get-post", http.MethodGet, "/posts/{id}")
+	registry.Add("delete-post", http.MethodDelete, "/posts/{id}")
+	registry.Child("/users").Add("get-user-post", http.MethodGet, "/{userID}/posts/{postID}/{$}")
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+		err      string
+	}{
+		{name: "URL", text: `{{ url "get-post" 42 }}`, expected: "/posts/42"},
+		{name: "URL with prefix", text: `{{ url "get-user-post" "a" "b" }}`, expected: "/users/a/posts/b/"},
+		{name: "URL with query", text: `{{ urlq "list-posts" "page" 2 }}`, expected: "/posts?page=2"},
+		{name: "URL with path params and query", text: `{{ urlq "get-post" 1 "tab" "comments" }}`, expected: "/posts/1?tab=comments"},
+		{name: "Method", text: `{{ method "delete-post" }}`, expected: "DELETE"},
+		{name: "Unknown route", text: `{{ url "unknown" }}`, err: `route "unknown" does not exist`},
+		{name: "Missing path param", text: `{{ url "get-post" }}`, err: `route "get-post" expects 1 path parameters, got 0`},
+		{name: "Extra path param", text: `{{ url "get-post" 1 2 }}`, err: `route "get-post" expects 1 path parameters, got 2`},
+		{name: "Odd query pairs", text: `{{ urlq "list-posts" "page" }}`, err: `route "list-posts" expects query pairs, got 1 values`},
+		{name: "Unknown method", text: `{{ method "unknown" }}`, err: `route "unknown" does not exist`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("test").Funcs(TemplateFuncs(registry)).Parse(tt.text))
+
+			var b strings.Builder
+
+			err := tmpl.Execute(&b, nil)
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Unexpected error: got=%v, want=%v", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: got=%v", err)
+			}
+
+			if b.String() != tt.expected {
+				t.Fatalf("Unexpected output: got=%q, want=%q", b.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncsFromContext(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add("get-post", http.MethodGet, "/posts/{id}")
+
+	funcs := TemplateFuncsFromContext(SetRegistry(context.Background(), registry))
+
+	tmpl := template.Must(template.New("test").Funcs(funcs).Parse(`{{ url "get-post" 1 }}`))
+
+	var b strings.Builder
+
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatalf("Unexpected error: got=%v", err)
+	}
+
+	if b.String() != "/posts/1" {
+		t.Fatalf("Unexpected output: got=%q, want=%q", b.String(), "/posts/1")
+	}
+
+	funcs = TemplateFuncsFromContext(context.Background())
+
+	tmpl = template.Must(template.New("test").Funcs(funcs).Parse(`{{ url "get-post" 1 }}`))
+
+	if err := tmpl.Execute(&b, nil); err == nil {
+		t.Fatalf("Unexpected error: got=nil")
+	}
+}
@@ -0,0 +1,195 @@
+package ki
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Versioning is an API versioning policy.
+// The version of a request is selected by its path prefix, e.g. "/v2/posts", by the Header or by the vendor media
+// type of its Accept header, e.g. "application/vnd.app.v2+json".
+type Versioning struct {
+	// Header is the request header selecting the version, "API-Version" by default.
+	// The served version is echoed in the same response header.
+	Header string
+	// Vendor is the vendor of the Accept media types selecting the version, e.g. "app".
+	// The Accept header is ignored if the vendor is empty.
+	Vendor string
+	// Default is the version served to the requests without version that match no other route.
+	Default string
+}
+
+// versioning dispatches the requests to the version sub-routers.
+type versioning struct {
+	policy Versioning
+	accept *regexp.Regexp
+	// versions maps the prefix of the routers to their versions.
+	versions map[string]*apiVersions
+}
+
+// apiVersions are the versions of a router.
+type apiVersions struct {
+	router   *Mux
+	versions []string
+}
+
+// WithVersioning returns a new MuxOption that sets the versioning policy of the sub-routers created with Version.
+func WithVersioning(policy Versioning) MuxOption {
+	return func(m *Mux) {
+		m.versioning = newVersioning(policy)
+	}
+}
+
+// newVersioning returns a new versioning.
+func newVersioning(policy Versioning) *versioning {
+	if policy.Header == "" {
+		policy.Header = "API-Version"
+	}
+
+	v := &versioning{
+		policy:   policy,
+		versions: map[string]*apiVersions{},
+	}
+
+	if policy.Vendor != "" {
+		v.accept = regexp.MustCompile(`vnd\.` + regexp.QuoteMeta(policy.Vendor) + `\.([^+;,\s]+)`)
+	}
+
+	return v
+}
+
+// Version creates a new router serving the given version of the API under the "/{version}" prefix.
+//
+// The requests without version prefix are routed to the version selected by the versioning policy, see
+// WithVersioning. The names of the routes added to the returned router are prefixed by the version, e.g. "v2.".
+func (m *Mux) Version(version string, fn func(Router)) Router {
+	root := m.root()
+
+	if root.versioning == nil {
+		root.versioning = newVersioning(Versioning{})
+	}
+
+	versions, ok := root.versioning.versions[m.prefix]
+	if !ok {
+		versions = &apiVersions{router: m}
+		root.versioning.versions[m.prefix] = versions
+	}
+
+	versions.versions = append(versions.versions, version)
+
+	mux := m.route("/" + version)
+	mux.setNamePrefix(m.namePrefix + version + ".")
+
+	if fn != nil {
+		fn(mux)
+	}
+
+	return mux
+}
+
+// Handler returns a handler that routes the request to the selected version before calling the given handler.
+// It responds 406 Not Acceptable if the requested version does not exist.
+func (v *versioning) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix, ok := v.prefix(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rest := r.URL.Path[len(prefix):]
+		versions := v.versions[prefix]
+
+		for _, version := range versions.versions {
+			if rest == "/"+version || strings.HasPrefix(rest, "/"+version+"/") {
+				w.Header().Set(v.policy.Header, version)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+		}
+
+		v.vary(w)
+
+		version := v.requested(r)
+
+		switch {
+		case version != "" && !slices.Contains(versions.versions, version):
+			http.Error(w, "unsupported API version", http.StatusNotAcceptable)
+			return
+		case version == "":
+			if versions.matches(r, rest) || !slices.Contains(versions.versions, v.policy.Default) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			version = v.policy.Default
+		}
+
+		w.Header().Set(v.policy.Header, version)
+		next.ServeHTTP(w, withPath(r, prefix+"/"+version+rest))
+	})
+}
+
+// matches returns true if the router of the versions has a route matching the request outside of the versions.
+func (v *apiVersions) matches(r *http.Request, rest string) bool {
+	// The router of a ServeMux matches the path stripped from its prefix.
+	if !v.router.flat {
+		if rest == "" {
+			rest = "/"
+		}
+
+		r = withPath(r, rest)
+	}
+
+	_, pattern := v.router.mux.Handler(r)
+
+	return pattern != ""
+}
+
+// prefix returns the longest prefix of the routers with versions matching the path.
+func (v *versioning) prefix(path string) (string, bool) {
+	longest, found := "", false
+
+	for prefix := range v.versions {
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+
+		if !found || len(prefix) > len(longest) {
+			longest, found = prefix, true
+		}
+	}
+
+	return longest, found
+}
+
+// requested returns the version requested by the header or the Accept header, or an empty string.
+func (v *versioning) requested(r *http.Request) string {
+	if version := strings.TrimSpace(r.Header.Get(v.policy.Header)); version != "" {
+		return version
+	}
+
+	if v.accept == nil {
+		return ""
+	}
+
+	for _, accept := range r.Header.Values("Accept") {
+		if match := v.accept.FindStringSubmatch(accept); match != nil {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// vary adds the headers selecting the version to the Vary header.
+func (v *versioning) vary(w http.ResponseWriter) {
+	w.Header().Add("Vary", v.policy.Header)
+
+	if v.accept != nil {
+		w.Header().Add("Vary", "Accept")
+	}
+}
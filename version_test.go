@@ -0,0 +1,123 @@
+package ki
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMux_Version(t *testing.T) {
+	for name, option := range map[string]MuxOption{"ServeMux": WithServeMux(), "RadixTree": WithRadixTree()} {
+		t.Run(name, func(t *testing.T) {
+			mux := NewMux(option, WithVersioning(Versioning{Vendor: "app", Default: "v1"}))
+
+			reply := func(body string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(body + " " + r.PathValue("id")))
+				}
+			}
+
+			mux.Get("/health", reply("health"))
+			mux.Version("v1", func(r Router) {
+				r.Get("/posts/{id}", reply("v1"), WithName("get-post"))
+			})
+			mux.Version("v2", func(r Router) {
+				r.Get("/posts/{id}", reply("v2"), WithName("get-post"))
+			})
+
+			tests := []struct {
+				name    string
+				path    string
+				headers map[string]string
+				code    int
+				body    string
+				version string
+				vary    bool
+			}{
+				{name: "Path", path: "/v2/posts/1", code: http.StatusOK, body: "v2 1", version: "v2"},
+				{name: "Header", path: "/posts/1", headers: map[string]string{"API-Version": "v2"}, code: http.StatusOK, body: "v2 1", version: "v2", vary: true},
+				{name: "Accept", path: "/posts/1", headers: map[string]string{"Accept": "application/vnd.app.v2+json"}, code: http.StatusOK, body: "v2 1", version: "v2", vary: true},
+				{name: "Default", path: "/posts/1", code: http.StatusOK, body: "v1 1", version: "v1", vary: true},
+				{name: "Unknown version", path: "/posts/1", headers: map[string]string{"API-Version": "v9"}, code: http.StatusNotAcceptable, vary: true},
+				{name: "Unversioned route", path: "/health", code: http.StatusOK, body: "health ", vary: true},
+				{name: "Missing route", path: "/v2/comments", code: http.StatusNotFound, version: "v2"},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+					for key, value := range tt.headers {
+						req.Header.Set(key, value)
+					}
+
+					rec := httptest.NewRecorder()
+
+					mux.ServeHTTP(rec, req)
+
+					if rec.Code != tt.code {
+						t.Fatalf("Unexpected status for %s: got=%d, want=%d", tt.path, rec.Code, tt.code)
+					}
+					if tt.body != "" && rec.Body.String() != tt.body {
+						t.Fatalf("Unexpected body for %s: got=%q, want=%q", tt.path, rec.Body.String(), tt.body)
+					}
+					if got := rec.Header().Get("API-Version"); got != tt.version {
+						t.Fatalf("Unexpected version for %s: got=%q, want=%q", tt.path, got, tt.version)
+					}
+
+					vary := rec.Header().Values("Vary")
+					if tt.vary && (len(vary) != 2 || vary[0] != "API-Version" || vary[1] != "Accept") {
+						t.Fatalf("Unexpected Vary for %s: got=%v", tt.path, vary)
+					}
+					if !tt.vary && len(vary) != 0 {
+						t.Fatalf("Unexpected Vary for %s: got=%v", tt.path, vary)
+					}
+				})
+			}
+
+			if got := mux.Registry().Get("v2.get-post").WithPathParams("1").URL().String(); got != "/v2/posts/1" {
+				t.Fatalf("Unexpected location: got=%s", got)
+			}
+		})
+	}
+}
+
+func TestMux_Version_Nested(t *testing.T) {
+	mux := NewMux(WithVersioning(Versioning{Header: "X-Version", Default: "v1"}))
+
+	mux.Route("/api", func(r Router) {
+		r.Version("v1", func(r Router) {
+			r.Get("/posts", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("v1"))
+			})
+		})
+		r.Version("v2", func(r Router) {
+			r.Get("/posts", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("v2"))
+			})
+		})
+	})
+
+	tests := map[string]string{
+		"":   "v1",
+		"v1": "v1",
+		"v2": "v2",
+	}
+
+	for version, expected := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+		if version != "" {
+			req.Header.Set("X-Version", version)
+		}
+
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		if rec.Body.String() != expected {
+			t.Fatalf("Unexpected body for version %q: got=%q, want=%q", version, rec.Body.String(), expected)
+		}
+		if got := rec.Header().Get("X-Version"); got != expected {
+			t.Fatalf("Unexpected version header for version %q: got=%q", version, got)
+		}
+	}
+}